@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/auth"
+	"violation-service/internal/config"
+	"violation-service/internal/db"
+	httphandler "violation-service/internal/http"
+	"violation-service/internal/openapi"
+	"violation-service/internal/policy"
+	"violation-service/internal/sla"
+)
+
+// runCheckConfig проверяет то, что serve проверил бы при старте, ничего не запуская.
+// Сам config.Load к этому моменту уже прошёл валидацию в main.
+func runCheckConfig(cfg *config.Config, log zerolog.Logger, args []string) int {
+	flags := flag.NewFlagSet("check-config", flag.ContinueOnError)
+	checkDB := flags.Bool("db", false, "connect to the database and report pending migrations")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	failed := false
+	report := func(name string, err error) {
+		if err != nil {
+			failed = true
+			fmt.Printf("FAIL  %-12s %v\n", name, err)
+			return
+		}
+		fmt.Printf("ok    %s\n", name)
+	}
+
+	_, err := policy.Load(cfg.Auth.PolicyFile)
+	report("policy", err)
+	_, err = sla.NewPolicy(cfg.SLA.Rules)
+	report("sla", err)
+	spec, err := openapi.Load()
+	report("openapi", err)
+	if spec != nil {
+		// Без release-режима Gin печатает каждый зарегистрированный маршрут.
+		gin.SetMode(gin.ReleaseMode)
+		report("routes", httphandler.CheckRoutes(spec))
+	}
+
+	if cfg.Auth.JWKSURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Auth.JWKSTimeout)
+		err = auth.NewKeySet(cfg.Auth.JWKSURL, cfg.Auth.JWKSTimeout, log).Refresh(ctx)
+		cancel()
+		report("jwks", err)
+	}
+
+	if *checkDB {
+		report("database", checkDatabase(cfg, log))
+	}
+
+	fmt.Printf("\nenvironment=%s addr=%s:%d algorithms=%s outbox=%s auto_migrate=%t\n",
+		cfg.Environment, cfg.HTTP.Host, cfg.HTTP.Port, strings.Join(cfg.Auth.Algorithms, ","),
+		cfg.Outbox.Publisher, cfg.DB.AutoMigrate)
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// checkDatabase проверяет соединение и требует, чтобы при выключенной автомиграции
+// все миграции были уже применены: иначе serve поднимется на старой схеме.
+func checkDatabase(cfg *config.Config, log zerolog.Logger) error {
+	autoMigrate := cfg.DB.AutoMigrate
+	cfg.DB.AutoMigrate = false
+	defer func() { cfg.DB.AutoMigrate = autoMigrate }()
+
+	database, err := db.New(context.Background(), cfg, log)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := db.HealthCheck(ctx, database); err != nil {
+		return err
+	}
+
+	migrator, err := db.NewMigrator(database, log)
+	if err != nil {
+		return err
+	}
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, status := range statuses {
+		switch status.State {
+		case db.MigrationModified:
+			return fmt.Errorf("migration %d %s: %w", status.Version, status.Name, db.ErrChecksumMismatch)
+		case db.MigrationPending:
+			pending++
+		}
+	}
+	if pending > 0 && !autoMigrate {
+		return fmt.Errorf("%d pending migrations and DB_AUTO_MIGRATE=false, run `migrate up`", pending)
+	}
+	if pending > 0 {
+		fmt.Fprintf(os.Stderr, "%d pending migrations will be applied on startup\n", pending)
+	}
+	return nil
+}
@@ -4,17 +4,31 @@ import (
 	"fmt"
 	"os"
 
-	"violation-service/internal/auth"
 	"violation-service/internal/config"
-	"violation-service/internal/db"
-	httphandler "violation-service/internal/http"
-	"violation-service/internal/http/middleware"
 	"violation-service/internal/logger"
-	"violation-service/internal/repository"
-	"violation-service/internal/service"
 )
 
+const usage = `usage: violation-service <command> [arguments]
+
+commands:
+  serve                     start the HTTP API and background workers (default)
+  migrate up                apply pending migrations
+  migrate down [steps]      revert the last migrations (default 1)
+  migrate status            list migrations and their state
+  seed [-token-ttl 24h]     create a stub shared schema and demo data, print demo tokens
+  check-config [-db]        validate configuration, policy and SLA rules; -db also checks the database
+`
+
 func main() {
+	command, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Print(usage)
+		return
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
@@ -23,27 +37,17 @@ func main() {
 
 	log := logger.New(cfg.Environment)
 
-	database, err := db.New(cfg, log)
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to connect to database")
-	}
-
-	scopeRepo := repository.NewScopeRepository(database)
-	violationRepo := repository.NewViolationRepository(database)
-	appealRepo := repository.NewAppealRepository(database)
-
-	violationService := service.NewViolationService(scopeRepo, violationRepo, appealRepo)
-	appealService := service.NewAppealService(scopeRepo, violationRepo, appealRepo, cfg.Files.MaxAttachmentsPerAction)
-
-	tokenParser := auth.NewParser(cfg.Auth.AccessSecret)
-
-	handler := httphandler.NewHandler(violationService, appealService, log)
-	router := httphandler.NewRouter(handler, middleware.Auth(tokenParser), cfg.Environment)
-
-	addr := fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port)
-	log.Info().Str("addr", addr).Msg("starting violations service")
-
-	if err := router.Run(addr); err != nil {
-		log.Fatal().Err(err).Msg("server stopped")
+	switch command {
+	case "serve":
+		runServe(cfg, log)
+	case "migrate":
+		os.Exit(runMigrate(cfg, log, args))
+	case "seed":
+		os.Exit(runSeed(cfg, log, args))
+	case "check-config":
+		os.Exit(runCheckConfig(cfg, log, args))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/config"
+	"violation-service/internal/db"
+)
+
+const migrateUsage = "usage: violation-service migrate up | down [steps] | status"
+
+// runMigrate выполняет `migrate up|down [steps]|status` и возвращает код выхода.
+func runMigrate(cfg *config.Config, log zerolog.Logger, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	// Команда сама решает, что применять: автомиграция при подключении не нужна.
+	cfg.DB.AutoMigrate = false
+	database, err := db.New(context.Background(), cfg, log)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to connect to database")
+		return 1
+	}
+	migrator, err := db.NewMigrator(database, log)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to init migrator")
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("migrate up failed")
+			return 1
+		}
+		log.Info().Int("applied", count).Msg("migrations are up to date")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, migrateUsage)
+				return 2
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Error().Err(err).Msg("migrate down failed")
+			return 1
+		}
+		log.Info().Int("reverted", count).Msg("migrations reverted")
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("migrate status failed")
+			return 1
+		}
+		printMigrationStatus(statuses)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+func printMigrationStatus(statuses []db.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+	}
+	w.Flush()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/auth"
+	"violation-service/internal/config"
+	"violation-service/internal/db"
+)
+
+// runSeed поднимает локальное окружение одной командой: заглушка общей схемы,
+// миграции, демо-данные и токены для каждой демо-роли.
+func runSeed(cfg *config.Config, log zerolog.Logger, args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	tokenTTL := flags.Duration("token-ttl", 24*time.Hour, "lifetime of the printed demo tokens")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if cfg.Environment == "production" {
+		fmt.Fprintln(os.Stderr, "seed is disabled when APP_ENV=production")
+		return 1
+	}
+
+	cfg.DB.AutoMigrate = false
+	database, err := db.New(context.Background(), cfg, log)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to connect to database")
+		return 1
+	}
+	if err := db.Seed(context.Background(), database, log); err != nil {
+		log.Error().Err(err).Msg("seed failed")
+		return 1
+	}
+
+	printDemoUsers(cfg, *tokenTTL)
+	return 0
+}
+
+// printDemoUsers выводит демо-пользователей; при разрешённом HS256 — вместе с токенами,
+// при RS*/ES* токены выпускает snowops-auth-service.
+func printDemoUsers(cfg *config.Config, ttl time.Duration) {
+	canSign := slices.Contains(cfg.Auth.Algorithms, "HS256") && cfg.Auth.AccessSecret != ""
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ROLE\tLOGIN\tUSER ID\tORG ID")
+	for _, user := range db.DemoUsers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", user.Role, user.Login, user.ID, user.OrgID)
+	}
+	w.Flush()
+
+	if !canSign {
+		fmt.Println("\nHS256 is not enabled: issue tokens for these users through snowops-auth-service.")
+		return
+	}
+
+	fmt.Println()
+	now := time.Now()
+	for _, user := range db.DemoUsers {
+		claims := auth.Claims{
+			SessionID: uuid.New(),
+			UserID:    user.ID,
+			OrgID:     user.OrgID,
+			Role:      user.Role,
+			DriverID:  user.DriverID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    cfg.Auth.Issuer,
+				Audience:  cfg.Auth.Audience,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			},
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Auth.AccessSecret))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "sign token for %s: %v\n", user.Login, err)
+			continue
+		}
+		name := strings.ToUpper(strings.NewReplacer(".", "_").Replace(user.Login))
+		fmt.Printf("export TOKEN_%s=%s\n", name, token)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/auth"
+	"violation-service/internal/config"
+	"violation-service/internal/db"
+	httphandler "violation-service/internal/http"
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/metrics"
+	"violation-service/internal/openapi"
+	"violation-service/internal/outbox"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+	"violation-service/internal/service"
+	"violation-service/internal/sla"
+	"violation-service/internal/tracing"
+	"violation-service/internal/webhook"
+)
+
+// runServe поднимает HTTP API и фоновые обработчики. По SIGTERM/SIGINT экземпляр
+// перестаёт быть готовым, дожидается текущих запросов и останавливает обработчики.
+func runServe(cfg *config.Config, log zerolog.Logger) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing, cfg.Environment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to init tracing")
+	}
+
+	database, err := db.New(ctx, cfg, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to database")
+	}
+	migrator, err := db.NewMigrator(database, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to init migrator")
+	}
+	readiness := db.NewReadiness(database, migrator)
+
+	// Обработчики живут в своём контексте: их останавливают после HTTP-сервера,
+	// чтобы запросы, дорабатывающие при остановке, успели записать события в outbox.
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	startWorker := func(run func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workersCtx)
+		}()
+	}
+
+	scopeRepo := repository.NewScopeRepository(database)
+	violationRepo := repository.NewViolationRepository(database)
+	appealRepo := repository.NewAppealRepository(database)
+	userRepo := repository.NewUserRepository(database)
+	uow := repository.NewUnitOfWork(database)
+
+	sqlDB, err := database.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to access connection pool")
+	}
+	if err := metrics.RegisterDB(sqlDB, violationRepo, appealRepo, log); err != nil {
+		log.Fatal().Err(err).Msg("failed to register database metrics")
+	}
+
+	accessPolicy, err := policy.Load(cfg.Auth.PolicyFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid access policy")
+	}
+
+	violationTypeRepo := repository.NewViolationTypeRepository(database)
+	violationService := service.NewViolationService(scopeRepo, violationRepo, appealRepo, userRepo, uow, violationTypeRepo, accessPolicy)
+	slaPolicy, err := sla.NewPolicy(cfg.SLA.Rules)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid appeal SLA configuration")
+	}
+	appealService := service.NewAppealService(scopeRepo, violationRepo, appealRepo, userRepo, uow, violationTypeRepo, slaPolicy, accessPolicy, cfg.Files.MaxAttachmentsPerAction)
+
+	webhookRepo := repository.NewWebhookRepository(database)
+
+	// Relay работает всегда: помимо внешнего приёмника он раскладывает события по вебхукам.
+	publishers := outbox.MultiPublisher{webhook.NewFanout(webhookRepo, scopeRepo, appealRepo)}
+	if cfg.Outbox.Publisher != "none" {
+		sink, err := newOutboxPublisher(cfg.Outbox)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to init outbox publisher")
+		}
+		publishers = append(publishers, sink)
+	}
+	relay := outbox.NewRelay(repository.NewOutboxRepository(database), publishers, outbox.RelayConfig{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		RetryBase:    cfg.Outbox.RetryBase,
+		RetryMax:     cfg.Outbox.RetryMax,
+		ClaimLease:   cfg.Outbox.ClaimLease,
+	}, log)
+	startWorker(relay.Run)
+
+	webhookWorker := webhook.NewWorker(webhookRepo, webhook.WorkerConfig{
+		PollInterval: cfg.Webhooks.PollInterval,
+		BatchSize:    cfg.Webhooks.BatchSize,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		RetryBase:    cfg.Webhooks.RetryBase,
+		RetryMax:     cfg.Webhooks.RetryMax,
+		HTTPTimeout:  cfg.Webhooks.HTTPTimeout,
+		ClaimLease:   cfg.Webhooks.ClaimLease,
+	}, log)
+	startWorker(webhookWorker.Run)
+
+	slaSweeper := sla.NewSweeper(appealService, sla.SweeperConfig{
+		Interval:  cfg.SLA.SweepInterval,
+		BatchSize: cfg.SLA.BatchSize,
+	}, log)
+	startWorker(slaSweeper.Run)
+
+	needInfoJob := sla.NewNeedInfoJob(appealService, sla.NeedInfoConfig{
+		Interval:      cfg.NeedInfo.SweepInterval,
+		BatchSize:     cfg.SLA.BatchSize,
+		DefaultWindow: cfg.NeedInfo.DefaultWindow,
+		Grace:         cfg.NeedInfo.Grace,
+	}, log)
+	startWorker(needInfoJob.Run)
+
+	var keySet *auth.KeySet
+	if cfg.Auth.JWKSURL != "" {
+		keySet = auth.NewKeySet(cfg.Auth.JWKSURL, cfg.Auth.JWKSTimeout, log)
+		if err := keySet.Refresh(ctx); err != nil {
+			log.Fatal().Err(err).Msg("failed to load JWKS")
+		}
+		startWorker(func(ctx context.Context) { keySet.Run(ctx, cfg.Auth.JWKSRefreshInterval) })
+	}
+	tokenParser := auth.NewParser(auth.ParserConfig{
+		Algorithms: cfg.Auth.Algorithms,
+		HMACSecret: cfg.Auth.AccessSecret,
+		Keys:       keySet,
+		Issuer:     cfg.Auth.Issuer,
+		Audience:   cfg.Auth.Audience,
+		Leeway:     cfg.Auth.Leeway,
+	})
+
+	sessionRepo := repository.NewSessionRepository(database)
+	revocations := auth.NewRevocationCache(sessionRepo, cfg.Auth.RevocationCacheTTL)
+
+	webhookService := service.NewWebhookService(webhookRepo, accessPolicy)
+	needInfoPolicyService := service.NewNeedInfoPolicyService(scopeRepo, repository.NewNeedInfoPolicyRepository(database), cfg.NeedInfo.DefaultWindow, accessPolicy)
+
+	sessionService := service.NewSessionService(scopeRepo, userRepo, sessionRepo, revocations, accessPolicy)
+
+	violationTypeService := service.NewViolationTypeService(violationTypeRepo, accessPolicy)
+	tripStatusRuleService := service.NewTripStatusRuleService(repository.NewTripStatusRuleRepository(database), violationTypeRepo, userRepo, accessPolicy)
+
+	handler := httphandler.NewHandler(violationService, appealService, webhookService, needInfoPolicyService, sessionService, violationTypeService, tripStatusRuleService)
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load OpenAPI spec")
+	}
+	router, err := httphandler.NewRouter(handler, middleware.Auth(tokenParser, revocations), readiness, spec, log, cfg.Environment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to build router")
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", cfg.HTTP.Host, cfg.HTTP.Port),
+		Handler:           router,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info().Str("addr", server.Addr).Msg("starting violations service")
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("server stopped")
+		}
+	case <-ctx.Done():
+		log.Info().Msg("shutdown signal received, draining")
+	}
+	stop()
+
+	readiness.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("http server did not drain in time")
+	}
+
+	stopWorkers()
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-shutdownCtx.Done():
+		log.Error().Msg("background workers did not stop in time")
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("failed to flush traces")
+	}
+	_ = sqlDB.Close()
+	log.Info().Msg("violations service stopped")
+}
+
+func newOutboxPublisher(cfg config.OutboxConfig) (outbox.Publisher, error) {
+	switch cfg.Publisher {
+	case "http":
+		return outbox.NewHTTPPublisher(cfg.HTTPURL, cfg.HTTPTimeout), nil
+	case "file":
+		return outbox.NewFilePublisher(cfg.FilePath)
+	default:
+		return outbox.NewFilePublisher("")
+	}
+}
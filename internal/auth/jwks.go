@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	maxJWKSSize = 1 << 20
+	// minJWKSRefetch ограничивает внеплановые перезагрузки при неизвестном kid,
+	// чтобы поток токенов с мусорным kid не превращался в поток запросов к JWKS.
+	minJWKSRefetch = 30 * time.Second
+)
+
+var ErrUnknownKey = errors.New("signing key not found")
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type verificationKey struct {
+	key crypto.PublicKey
+	alg string
+}
+
+// KeySet хранит публичные ключи из JWKS-документа (файл или URL) и периодически
+// перечитывает его. Одновременно доступны все ключи документа, поэтому при ротации
+// достаточно опубликовать новый ключ рядом со старым.
+type KeySet struct {
+	source string
+	client *http.Client
+	log    zerolog.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]verificationKey
+	refreshed time.Time
+
+	refetchMu sync.Mutex
+}
+
+func NewKeySet(source string, timeout time.Duration, log zerolog.Logger) *KeySet {
+	return &KeySet{
+		source: source,
+		client: &http.Client{Timeout: timeout},
+		log:    log.With().Str("component", "jwks").Logger(),
+		keys:   map[string]verificationKey{},
+	}
+}
+
+// Refresh загружает документ и атомарно заменяет набор ключей. При ошибке прежний
+// набор остаётся в силе.
+func (k *KeySet) Refresh(ctx context.Context) error {
+	data, err := k.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	k.keys = keys
+	k.refreshed = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+// Run перечитывает документ каждые interval до отмены ctx.
+func (k *KeySet) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.Refresh(ctx); err != nil {
+				k.log.Error().Err(err).Msg("failed to refresh JWKS, keeping previous keys")
+			}
+		}
+	}
+}
+
+// Key ищет ключ по kid. Пустой kid допустим, только если ключ в наборе один.
+// Неизвестный kid вызывает внеплановую перезагрузку: токены, подписанные только что
+// опубликованным ключом, не должны ждать следующего периода.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, string, error) {
+	if key, ok := k.lookup(kid); ok {
+		return key.key, key.alg, nil
+	}
+
+	k.refetchMu.Lock()
+	defer k.refetchMu.Unlock()
+	k.mu.RLock()
+	stale := time.Since(k.refreshed) >= minJWKSRefetch
+	k.mu.RUnlock()
+	if stale {
+		if err := k.Refresh(ctx); err != nil {
+			k.log.Warn().Err(err).Str("kid", kid).Msg("JWKS refetch for unknown kid failed")
+		}
+	}
+
+	if key, ok := k.lookup(kid); ok {
+		return key.key, key.alg, nil
+	}
+	return nil, "", ErrUnknownKey
+}
+
+func (k *KeySet) lookup(kid string) (verificationKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if kid == "" {
+		if len(k.keys) != 1 {
+			return verificationKey{}, false
+		}
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+func (k *KeySet) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(k.source, "http://") && !strings.HasPrefix(k.source, "https://") {
+		return os.ReadFile(strings.TrimPrefix(k.source, "file://"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// parseJWKS разбирает RSA- и EC-ключи подписи; ключи других типов и ключи шифрования
+// пропускаются, а не ломают весь документ.
+func parseJWKS(data []byte) (map[string]verificationKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(doc.Keys))
+	for _, raw := range doc.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch raw.Kty {
+		case "RSA":
+			key, err = parseRSAKey(raw)
+		case "EC":
+			key, err = parseECKey(raw)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwks: key %q: %w", raw.Kid, err)
+		}
+		if _, dup := keys[raw.Kid]; dup {
+			return nil, fmt.Errorf("jwks: duplicate kid %q", raw.Kid)
+		}
+		keys[raw.Kid] = verificationKey{key: key, alg: raw.Alg}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks: no usable signing keys")
+	}
+	return keys, nil
+}
+
+func parseRSAKey(raw jwk) (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(raw.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := decodeBigInt(raw.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+	if n.BitLen() < 2048 {
+		return nil, errors.New("RSA modulus shorter than 2048 bits")
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseECKey(raw jwk) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch raw.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", raw.Crv)
+	}
+	x, err := decodeBigInt(raw.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := decodeBigInt(raw.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("point is not on curve")
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("missing value")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func b64(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func rsaJWK(t *testing.T, kid string, key *rsa.PublicKey) jwk {
+	t.Helper()
+	return jwk{Kty: "RSA", Kid: kid, Use: "sig", N: b64(key.N), E: b64(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(t *testing.T, kid, crv string, key *ecdsa.PublicKey) jwk {
+	t.Helper()
+	return jwk{Kty: "EC", Kid: kid, Crv: crv, X: b64(key.X), Y: b64(key.Y)}
+}
+
+func jwksDocument(t *testing.T, keys ...jwk) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string][]jwk{"keys": keys})
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+	return data
+}
+
+func generateRSA(t *testing.T, bits int) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	return key
+}
+
+func generateEC(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatalf("generate EC key: %v", err)
+	}
+	return key
+}
+
+func TestParseJWKSRSA(t *testing.T) {
+	key := generateRSA(t, 2048)
+	keys, err := parseJWKS(jwksDocument(t, rsaJWK(t, "rsa-1", &key.PublicKey)))
+	if err != nil {
+		t.Fatalf("parseJWKS: %v", err)
+	}
+	got, ok := keys["rsa-1"].key.(*rsa.PublicKey)
+	if !ok || !got.Equal(&key.PublicKey) {
+		t.Fatalf("parsed key = %#v, want the generated public key", keys["rsa-1"].key)
+	}
+}
+
+func TestParseJWKSRejectsShortRSA(t *testing.T) {
+	key := generateRSA(t, 1024)
+	_, err := parseJWKS(jwksDocument(t, rsaJWK(t, "weak", &key.PublicKey)))
+	if err == nil || !strings.Contains(err.Error(), "2048") {
+		t.Fatalf("parseJWKS error = %v, want modulus length error", err)
+	}
+}
+
+func TestParseJWKSCurves(t *testing.T) {
+	tests := []struct {
+		crv   string
+		curve elliptic.Curve
+	}{
+		{crv: "P-256", curve: elliptic.P256()},
+		{crv: "P-384", curve: elliptic.P384()},
+		{crv: "P-521", curve: elliptic.P521()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.crv, func(t *testing.T) {
+			key := generateEC(t, tt.curve)
+			keys, err := parseJWKS(jwksDocument(t, ecJWK(t, "ec", tt.crv, &key.PublicKey)))
+			if err != nil {
+				t.Fatalf("parseJWKS: %v", err)
+			}
+			got, ok := keys["ec"].key.(*ecdsa.PublicKey)
+			if !ok || !got.Equal(&key.PublicKey) {
+				t.Fatalf("parsed key = %#v, want the generated public key", keys["ec"].key)
+			}
+		})
+	}
+}
+
+func TestParseJWKSRejectsBadECKeys(t *testing.T) {
+	p256 := generateEC(t, elliptic.P256())
+	p384 := generateEC(t, elliptic.P384())
+
+	tests := []struct {
+		name    string
+		key     jwk
+		wantErr string
+	}{
+		{name: "unsupported curve", key: ecJWK(t, "ec", "secp256k1", &p256.PublicKey), wantErr: "unsupported curve"},
+		{name: "point of another curve", key: ecJWK(t, "ec", "P-256", &p384.PublicKey), wantErr: "not on curve"},
+		{name: "missing y", key: jwk{Kty: "EC", Kid: "ec", Crv: "P-256", X: b64(p256.X)}, wantErr: "missing value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseJWKS(jwksDocument(t, tt.key))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("parseJWKS error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseJWKSSkipsUnusableKeys(t *testing.T) {
+	rsaKey := generateRSA(t, 2048)
+	enc := rsaJWK(t, "enc", &rsaKey.PublicKey)
+	enc.Use = "enc"
+	oct := jwk{Kty: "oct", Kid: "hmac"}
+
+	if _, err := parseJWKS(jwksDocument(t, enc, oct)); err == nil {
+		t.Fatal("parseJWKS accepted a document without signing keys")
+	}
+
+	keys, err := parseJWKS(jwksDocument(t, enc, oct, rsaJWK(t, "sig", &rsaKey.PublicKey)))
+	if err != nil {
+		t.Fatalf("parseJWKS: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("parsed %d keys, want only the signing key", len(keys))
+	}
+}
+
+func TestParseJWKSRejectsDuplicateKid(t *testing.T) {
+	key := generateEC(t, elliptic.P256())
+	doc := jwksDocument(t, ecJWK(t, "dup", "P-256", &key.PublicKey), ecJWK(t, "dup", "P-256", &key.PublicKey))
+	if _, err := parseJWKS(doc); err == nil || !strings.Contains(err.Error(), "duplicate kid") {
+		t.Fatalf("parseJWKS error = %v, want duplicate kid", err)
+	}
+}
+
+// jwksServer отдаёт текущий документ и считает запросы.
+type jwksServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	doc  []byte
+	hits atomic.Int32
+}
+
+func newJWKSServer(t *testing.T, doc []byte) *jwksServer {
+	t.Helper()
+	s := &jwksServer{doc: doc}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.hits.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, _ = w.Write(s.doc)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) publish(doc []byte) {
+	s.mu.Lock()
+	s.doc = doc
+	s.mu.Unlock()
+}
+
+func TestKeySetRefetchesUnknownKidAtMostEvery30s(t *testing.T) {
+	ctx := context.Background()
+	oldKey := generateEC(t, elliptic.P256())
+	newKey := generateEC(t, elliptic.P256())
+
+	server := newJWKSServer(t, jwksDocument(t, ecJWK(t, "old", "P-256", &oldKey.PublicKey)))
+	keySet := NewKeySet(server.URL, time.Second, zerolog.Nop())
+	if err := keySet.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	server.publish(jwksDocument(t,
+		ecJWK(t, "old", "P-256", &oldKey.PublicKey),
+		ecJWK(t, "new", "P-256", &newKey.PublicKey),
+	))
+
+	// Сразу после загрузки неизвестный kid не должен вызывать запрос к JWKS.
+	if _, _, err := keySet.Key(ctx, "new"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(new) error = %v, want ErrUnknownKey", err)
+	}
+	if hits := server.hits.Load(); hits != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", hits)
+	}
+
+	keySet.mu.Lock()
+	keySet.refreshed = time.Now().Add(-minJWKSRefetch)
+	keySet.mu.Unlock()
+
+	key, _, err := keySet.Key(ctx, "new")
+	if err != nil {
+		t.Fatalf("Key(new) after refetch window: %v", err)
+	}
+	if got, ok := key.(*ecdsa.PublicKey); !ok || !got.Equal(&newKey.PublicKey) {
+		t.Fatalf("Key(new) = %#v, want the newly published key", key)
+	}
+	if hits := server.hits.Load(); hits != 2 {
+		t.Fatalf("JWKS fetched %d times, want 2", hits)
+	}
+
+	if _, _, err := keySet.Key(ctx, "missing"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(missing) error = %v, want ErrUnknownKey", err)
+	}
+	if hits := server.hits.Load(); hits != 2 {
+		t.Fatalf("JWKS fetched %d times after a fresh refetch, want 2", hits)
+	}
+}
+
+func TestKeySetEmptyKid(t *testing.T) {
+	ctx := context.Background()
+	first := generateEC(t, elliptic.P256())
+	second := generateEC(t, elliptic.P256())
+
+	server := newJWKSServer(t, jwksDocument(t, ecJWK(t, "only", "P-256", &first.PublicKey)))
+	keySet := NewKeySet(server.URL, time.Second, zerolog.Nop())
+	if err := keySet.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, _, err := keySet.Key(ctx, ""); err != nil {
+		t.Fatalf("Key(\"\") with a single key: %v", err)
+	}
+
+	server.publish(jwksDocument(t,
+		ecJWK(t, "a", "P-256", &first.PublicKey),
+		ecJWK(t, "b", "P-256", &second.PublicKey),
+	))
+	if err := keySet.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if _, _, err := keySet.Key(ctx, ""); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(\"\") with two keys error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestKeySetRefreshKeepsKeysOnError(t *testing.T) {
+	ctx := context.Background()
+	key := generateEC(t, elliptic.P256())
+
+	server := newJWKSServer(t, jwksDocument(t, ecJWK(t, "k", "P-256", &key.PublicKey)))
+	keySet := NewKeySet(server.URL, time.Second, zerolog.Nop())
+	if err := keySet.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	server.publish([]byte(`{"keys": []}`))
+	if err := keySet.Refresh(ctx); err == nil {
+		t.Fatal("Refresh accepted an empty key set")
+	}
+	if _, _, err := keySet.Key(ctx, "k"); err != nil {
+		t.Fatalf("Key(k) after failed refresh: %v", err)
+	}
+}
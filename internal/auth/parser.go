@@ -1,6 +1,11 @@
 package auth
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
@@ -16,18 +21,45 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// ParserConfig описывает, какие токены принимаются. Keys нужен для RS*/ES*,
+// HMACSecret — для HS*; алгоритмы вне Algorithms отклоняются ещё до выбора ключа.
+type ParserConfig struct {
+	Algorithms []string
+	HMACSecret string
+	Keys       *KeySet
+	Issuer     string
+	Audience   []string
+	Leeway     time.Duration
+}
+
 type Parser struct {
-	secret []byte
+	secret  []byte
+	keys    *KeySet
+	options []jwt.ParserOption
 }
 
-func NewParser(secret string) *Parser {
-	return &Parser{secret: []byte(secret)}
+func NewParser(cfg ParserConfig) *Parser {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if len(cfg.Audience) > 0 {
+		options = append(options, jwt.WithAudience(cfg.Audience...))
+	}
+	return &Parser{
+		secret:  []byte(cfg.HMACSecret),
+		keys:    cfg.Keys,
+		options: options,
+	}
 }
 
-func (p *Parser) Parse(tokenStr string) (*Claims, error) {
+func (p *Parser) Parse(ctx context.Context, tokenStr string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return p.secret, nil
-	})
+		return p.keyFor(ctx, token)
+	}, p.options...)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +71,30 @@ func (p *Parser) Parse(tokenStr string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// keyFor выбирает ключ по семейству алгоритма: общий секрет для HMAC, ключ из JWKS
+// по kid для RSA/ECDSA. Если у ключа в JWKS указан alg, он должен совпасть с токеном.
+func (p *Parser) keyFor(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(p.secret) == 0 {
+			return nil, errors.New("HMAC tokens are not accepted")
+		}
+		return p.secret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		if p.keys == nil {
+			return nil, errors.New("asymmetric tokens are not accepted")
+		}
+		kid, _ := token.Header["kid"].(string)
+		key, alg, err := p.keys.Key(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if alg != "" && alg != token.Method.Alg() {
+			return nil, fmt.Errorf("key %q is bound to %s", kid, alg)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing method %s", token.Method.Alg())
+	}
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"crypto/elliptic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/model"
+)
+
+const (
+	testIssuer   = "snowops-auth-service"
+	testAudience = "violation-service"
+)
+
+func testClaims(issuer, audience string) Claims {
+	now := time.Now()
+	return Claims{
+		SessionID: uuid.New(),
+		UserID:    uuid.New(),
+		OrgID:     uuid.New(),
+		Role:      model.UserRoleAkimatAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign %s token: %v", method.Alg(), err)
+	}
+	return signed
+}
+
+func TestParserVerifiesAsymmetricTokens(t *testing.T) {
+	ctx := context.Background()
+	rsaKey := generateRSA(t, 2048)
+	ecKey := generateEC(t, elliptic.P256())
+	otherEC := generateEC(t, elliptic.P256())
+
+	boundRSA := rsaJWK(t, "rsa", &rsaKey.PublicKey)
+	boundRSA.Alg = "RS256"
+	server := newJWKSServer(t, jwksDocument(t, boundRSA, ecJWK(t, "ec", "P-256", &ecKey.PublicKey)))
+	keySet := NewKeySet(server.URL, time.Second, zerolog.Nop())
+	if err := keySet.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+
+	parser := NewParser(ParserConfig{
+		Algorithms: []string{"RS256", "RS512", "ES256"},
+		HMACSecret: "secret",
+		Keys:       keySet,
+		Issuer:     testIssuer,
+		Audience:   []string{testAudience},
+	})
+	valid := testClaims(testIssuer, testAudience)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "RS256", token: sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, valid)},
+		{name: "ES256", token: sign(t, jwt.SigningMethodES256, "ec", ecKey, valid)},
+		{name: "wrong issuer", token: sign(t, jwt.SigningMethodES256, "ec", ecKey, testClaims("someone-else", testAudience)), wantErr: true},
+		{name: "wrong audience", token: sign(t, jwt.SigningMethodES256, "ec", ecKey, testClaims(testIssuer, "billing-service")), wantErr: true},
+		{name: "algorithm not allowed", token: sign(t, jwt.SigningMethodHS256, "", []byte("secret"), valid), wantErr: true},
+		{name: "algorithm differs from key alg", token: sign(t, jwt.SigningMethodRS512, "rsa", rsaKey, valid), wantErr: true},
+		{name: "signed by another key", token: sign(t, jwt.SigningMethodES256, "ec", otherEC, valid), wantErr: true},
+		{name: "unknown kid", token: sign(t, jwt.SigningMethodES256, "missing", ecKey, valid), wantErr: true},
+		{name: "unsigned", token: sign(t, jwt.SigningMethodNone, "ec", jwt.UnsafeAllowNoneSignatureType, valid), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := parser.Parse(ctx, tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Parse accepted the token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if claims.UserID != valid.UserID || claims.SessionID != valid.SessionID {
+				t.Errorf("claims = %+v, want %+v", claims, valid)
+			}
+		})
+	}
+}
+
+func TestParserHMAC(t *testing.T) {
+	ctx := context.Background()
+	claims := testClaims("", testAudience)
+
+	parser := NewParser(ParserConfig{Algorithms: []string{"HS256"}, HMACSecret: "secret"})
+	if _, err := parser.Parse(ctx, sign(t, jwt.SigningMethodHS256, "", []byte("secret"), claims)); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := parser.Parse(ctx, sign(t, jwt.SigningMethodHS256, "", []byte("other"), claims)); err == nil {
+		t.Fatal("Parse accepted a token signed with another secret")
+	}
+
+	expired := claims
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	if _, err := parser.Parse(ctx, sign(t, jwt.SigningMethodHS256, "", []byte("secret"), expired)); err == nil {
+		t.Fatal("Parse accepted an expired token")
+	}
+
+	lenient := NewParser(ParserConfig{Algorithms: []string{"HS256"}, HMACSecret: "secret", Leeway: 2 * time.Minute})
+	if _, err := lenient.Parse(ctx, sign(t, jwt.SigningMethodHS256, "", []byte("secret"), expired)); err != nil {
+		t.Fatalf("Parse with leeway: %v", err)
+	}
+
+	asymmetricOnly := NewParser(ParserConfig{Algorithms: []string{"HS256", "ES256"}})
+	if _, err := asymmetricOnly.Parse(ctx, sign(t, jwt.SigningMethodHS256, "", []byte(""), claims)); err == nil {
+		t.Fatal("Parse accepted an HMAC token without a configured secret")
+	}
+}
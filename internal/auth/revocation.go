@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevocationStore — постоянное хранилище отзывов (см. repository.SessionRepository).
+type RevocationStore interface {
+	IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	UserRevokedBefore(ctx context.Context, userID uuid.UUID) (*time.Time, error)
+}
+
+type revocationEntry struct {
+	revoked bool
+	before  *time.Time
+	expires time.Time
+}
+
+// RevocationCache кэширует ответы хранилища на ttl, чтобы проверка отзыва не стоила
+// двух запросов к БД на каждый HTTP-запрос. Кэшируются и отрицательные ответы, поэтому
+// отзыв, сделанный через другой экземпляр сервиса, вступает в силу не позже чем через ttl;
+// на своём экземпляре Forget* применяет его сразу.
+type RevocationCache struct {
+	store RevocationStore
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	sessions  map[uuid.UUID]revocationEntry
+	users     map[uuid.UUID]revocationEntry
+	nextPurge time.Time
+}
+
+func NewRevocationCache(store RevocationStore, ttl time.Duration) *RevocationCache {
+	return &RevocationCache{
+		store:    store,
+		ttl:      ttl,
+		sessions: map[uuid.UUID]revocationEntry{},
+		users:    map[uuid.UUID]revocationEntry{},
+	}
+}
+
+// IsRevoked проверяет сессию токена и границу отзыва его пользователя. Токен без iat
+// при наличии границы считается отозванным: момент его выдачи неизвестен.
+func (c *RevocationCache) IsRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	if claims.SessionID != uuid.Nil {
+		revoked, err := c.sessionRevoked(ctx, claims.SessionID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	before, err := c.userRevokedBefore(ctx, claims.UserID)
+	if err != nil || before == nil {
+		return false, err
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return claims.IssuedAt.Before(*before), nil
+}
+
+func (c *RevocationCache) ForgetSession(sessionID uuid.UUID) {
+	c.mu.Lock()
+	delete(c.sessions, sessionID)
+	c.mu.Unlock()
+}
+
+func (c *RevocationCache) ForgetUser(userID uuid.UUID) {
+	c.mu.Lock()
+	delete(c.users, userID)
+	c.mu.Unlock()
+}
+
+func (c *RevocationCache) sessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	if entry, ok := c.get(c.sessions, sessionID); ok {
+		return entry.revoked, nil
+	}
+	revoked, err := c.store.IsSessionRevoked(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	c.put(c.sessions, sessionID, revocationEntry{revoked: revoked})
+	return revoked, nil
+}
+
+func (c *RevocationCache) userRevokedBefore(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	if entry, ok := c.get(c.users, userID); ok {
+		return entry.before, nil
+	}
+	before, err := c.store.UserRevokedBefore(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	c.put(c.users, userID, revocationEntry{before: before})
+	return before, nil
+}
+
+func (c *RevocationCache) get(entries map[uuid.UUID]revocationEntry, key uuid.UUID) (revocationEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return revocationEntry{}, false
+	}
+	return entry, true
+}
+
+// put сохраняет запись и раз в ttl вычищает просроченные, чтобы кэш не рос
+// вместе с числом когда-либо виденных сессий.
+func (c *RevocationCache) put(entries map[uuid.UUID]revocationEntry, key uuid.UUID, entry revocationEntry) {
+	now := time.Now()
+	entry.expires = now.Add(c.ttl)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries[key] = entry
+	if now.Before(c.nextPurge) {
+		return
+	}
+	c.nextPurge = now.Add(c.ttl)
+	for _, m := range []map[uuid.UUID]revocationEntry{c.sessions, c.users} {
+		for k, e := range m {
+			if now.After(e.expires) {
+				delete(m, k)
+			}
+		}
+	}
+}
@@ -2,14 +2,21 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type HTTPConfig struct {
-	Host string
-	Port int
+	Host              string
+	Port              int
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
 }
 
 type DBConfig struct {
@@ -17,22 +24,87 @@ type DBConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	AutoMigrate     bool
+	ConnectTimeout  time.Duration
 }
 
 type AuthConfig struct {
-	AccessSecret string
+	AccessSecret        string
+	PolicyFile          string
+	Algorithms          []string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	JWKSTimeout         time.Duration
+	Issuer              string
+	Audience            []string
+	Leeway              time.Duration
+	RevocationCacheTTL  time.Duration
 }
 
 type FilesConfig struct {
 	MaxAttachmentsPerAction int
 }
 
+type OutboxConfig struct {
+	Publisher    string
+	FilePath     string
+	HTTPURL      string
+	HTTPTimeout  time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	ClaimLease   time.Duration
+}
+
+type WebhookConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	HTTPTimeout  time.Duration
+	ClaimLease   time.Duration
+}
+
+type SLAConfig struct {
+	Rules         map[string]time.Duration
+	SweepInterval time.Duration
+	BatchSize     int
+}
+
+type NeedInfoConfig struct {
+	DefaultWindow time.Duration
+	Grace         time.Duration
+	SweepInterval time.Duration
+}
+
+// TracingConfig — экспорт трассировок OpenTelemetry; Exporter "none" только пробрасывает traceparent.
+type TracingConfig struct {
+	Exporter     string
+	OTLPEndpoint string
+	FilePath     string
+	SampleRatio  float64
+	ServiceName  string
+}
+
+const defaultAppealSLA = "SUBMITTED=48h,UNDER_REVIEW=120h,NEED_INFO=120h"
+
+// supportedAlgorithms — алгоритмы, которые можно перечислить в JWT_ALGORITHMS; auth.Parser
+// умеет проверять подписи каждого из них.
+var supportedAlgorithms = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
 type Config struct {
 	Environment string
 	HTTP        HTTPConfig
 	DB          DBConfig
 	Auth        AuthConfig
 	Files       FilesConfig
+	Outbox      OutboxConfig
+	Webhooks    WebhookConfig
+	SLA         SLAConfig
+	NeedInfo    NeedInfoConfig
+	Tracing     TracingConfig
 }
 
 func Load() (*Config, error) {
@@ -45,28 +117,92 @@ func Load() (*Config, error) {
 	v.AddConfigPath("./internal/config")
 
 	v.AutomaticEnv()
+	v.SetDefault("DB_AUTO_MIGRATE", true)
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
 
 	_ = v.ReadInConfig()
 
 	cfg := &Config{
 		Environment: v.GetString("APP_ENV"),
 		HTTP: HTTPConfig{
-			Host: v.GetString("HTTP_HOST"),
-			Port: v.GetInt("HTTP_PORT"),
+			Host:              v.GetString("HTTP_HOST"),
+			Port:              v.GetInt("HTTP_PORT"),
+			ReadTimeout:       v.GetDuration("HTTP_READ_TIMEOUT"),
+			ReadHeaderTimeout: v.GetDuration("HTTP_READ_HEADER_TIMEOUT"),
+			WriteTimeout:      v.GetDuration("HTTP_WRITE_TIMEOUT"),
+			IdleTimeout:       v.GetDuration("HTTP_IDLE_TIMEOUT"),
+			ShutdownTimeout:   v.GetDuration("HTTP_SHUTDOWN_TIMEOUT"),
 		},
 		DB: DBConfig{
 			DSN:             v.GetString("DB_DSN"),
 			MaxOpenConns:    v.GetInt("DB_MAX_OPEN_CONNS"),
 			MaxIdleConns:    v.GetInt("DB_MAX_IDLE_CONNS"),
 			ConnMaxLifetime: v.GetDuration("DB_CONN_MAX_LIFETIME"),
+			AutoMigrate:     v.GetBool("DB_AUTO_MIGRATE"),
+			ConnectTimeout:  v.GetDuration("DB_CONNECT_TIMEOUT"),
 		},
 		Auth: AuthConfig{
-			AccessSecret: v.GetString("JWT_ACCESS_SECRET"),
+			AccessSecret:        v.GetString("JWT_ACCESS_SECRET"),
+			PolicyFile:          v.GetString("POLICY_FILE"),
+			Algorithms:          splitList(strings.ToUpper(v.GetString("JWT_ALGORITHMS"))),
+			JWKSURL:             strings.TrimSpace(v.GetString("JWT_JWKS_URL")),
+			JWKSRefreshInterval: v.GetDuration("JWT_JWKS_REFRESH_INTERVAL"),
+			JWKSTimeout:         v.GetDuration("JWT_JWKS_TIMEOUT"),
+			Issuer:              strings.TrimSpace(v.GetString("JWT_ISSUER")),
+			Audience:            splitList(v.GetString("JWT_AUDIENCE")),
+			Leeway:              v.GetDuration("JWT_LEEWAY"),
+			RevocationCacheTTL:  v.GetDuration("SESSION_REVOCATION_CACHE_TTL"),
 		},
 		Files: FilesConfig{
 			MaxAttachmentsPerAction: v.GetInt("APPEAL_MAX_ATTACHMENTS"),
 		},
+		Outbox: OutboxConfig{
+			Publisher:    strings.ToLower(v.GetString("OUTBOX_PUBLISHER")),
+			FilePath:     v.GetString("OUTBOX_FILE_PATH"),
+			HTTPURL:      v.GetString("OUTBOX_HTTP_URL"),
+			HTTPTimeout:  v.GetDuration("OUTBOX_HTTP_TIMEOUT"),
+			PollInterval: v.GetDuration("OUTBOX_POLL_INTERVAL"),
+			BatchSize:    v.GetInt("OUTBOX_BATCH_SIZE"),
+			RetryBase:    v.GetDuration("OUTBOX_RETRY_BASE"),
+			RetryMax:     v.GetDuration("OUTBOX_RETRY_MAX"),
+			ClaimLease:   v.GetDuration("OUTBOX_CLAIM_LEASE"),
+		},
+		Webhooks: WebhookConfig{
+			PollInterval: v.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			BatchSize:    v.GetInt("WEBHOOK_BATCH_SIZE"),
+			MaxAttempts:  v.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			RetryBase:    v.GetDuration("WEBHOOK_RETRY_BASE"),
+			RetryMax:     v.GetDuration("WEBHOOK_RETRY_MAX"),
+			HTTPTimeout:  v.GetDuration("WEBHOOK_HTTP_TIMEOUT"),
+			ClaimLease:   v.GetDuration("WEBHOOK_CLAIM_LEASE"),
+		},
+		SLA: SLAConfig{
+			SweepInterval: v.GetDuration("APPEAL_SLA_SWEEP_INTERVAL"),
+			BatchSize:     v.GetInt("APPEAL_SLA_BATCH_SIZE"),
+		},
+		NeedInfo: NeedInfoConfig{
+			DefaultWindow: v.GetDuration("NEED_INFO_WINDOW"),
+			Grace:         v.GetDuration("NEED_INFO_GRACE"),
+			SweepInterval: v.GetDuration("NEED_INFO_SWEEP_INTERVAL"),
+		},
+		Tracing: TracingConfig{
+			Exporter:     strings.ToLower(v.GetString("TRACING_EXPORTER")),
+			OTLPEndpoint: strings.TrimSpace(v.GetString("TRACING_OTLP_ENDPOINT")),
+			FilePath:     v.GetString("TRACING_FILE_PATH"),
+			SampleRatio:  v.GetFloat64("TRACING_SAMPLE_RATIO"),
+			ServiceName:  v.GetString("TRACING_SERVICE_NAME"),
+		},
+	}
+
+	slaRules := v.GetString("APPEAL_SLA")
+	if strings.TrimSpace(slaRules) == "" {
+		slaRules = defaultAppealSLA
+	}
+	rules, err := parseDurationRules(slaRules)
+	if err != nil {
+		return nil, fmt.Errorf("APPEAL_SLA: %w", err)
 	}
+	cfg.SLA.Rules = rules
 
 	if cfg.HTTP.Host == "" {
 		cfg.HTTP.Host = "0.0.0.0"
@@ -74,12 +210,114 @@ func Load() (*Config, error) {
 	if cfg.HTTP.Port == 0 {
 		cfg.HTTP.Port = 7086
 	}
+	if cfg.HTTP.ReadTimeout <= 0 {
+		cfg.HTTP.ReadTimeout = 30 * time.Second
+	}
+	if cfg.HTTP.ReadHeaderTimeout <= 0 {
+		cfg.HTTP.ReadHeaderTimeout = 5 * time.Second
+	}
+	if cfg.HTTP.WriteTimeout <= 0 {
+		// Экспорт нарушений не ограничен этим значением: он сам продлевает дедлайн после каждой пачки.
+		cfg.HTTP.WriteTimeout = 2 * time.Minute
+	}
+	if cfg.HTTP.IdleTimeout <= 0 {
+		cfg.HTTP.IdleTimeout = 2 * time.Minute
+	}
+	if cfg.HTTP.ShutdownTimeout <= 0 {
+		cfg.HTTP.ShutdownTimeout = 30 * time.Second
+	}
+	if cfg.DB.ConnectTimeout <= 0 {
+		cfg.DB.ConnectTimeout = time.Minute
+	}
 	if cfg.Environment == "" {
 		cfg.Environment = "development"
 	}
+	if len(cfg.Auth.Algorithms) == 0 {
+		// Без JWKS сервис работает в прежнем режиме HMAC.
+		if cfg.Auth.JWKSURL != "" {
+			cfg.Auth.Algorithms = []string{"RS256", "ES256"}
+		} else {
+			cfg.Auth.Algorithms = []string{"HS256"}
+		}
+	}
+	if cfg.Auth.JWKSRefreshInterval <= 0 {
+		cfg.Auth.JWKSRefreshInterval = 5 * time.Minute
+	}
+	if cfg.Auth.JWKSTimeout <= 0 {
+		cfg.Auth.JWKSTimeout = 5 * time.Second
+	}
+	if cfg.Auth.RevocationCacheTTL <= 0 {
+		cfg.Auth.RevocationCacheTTL = 30 * time.Second
+	}
 	if cfg.Files.MaxAttachmentsPerAction <= 0 {
 		cfg.Files.MaxAttachmentsPerAction = 5
 	}
+	if cfg.Outbox.Publisher == "" {
+		// stdout смешивает полные события (с текстами апелляций) с логами, поэтому
+		// включается только явно, для разработки.
+		cfg.Outbox.Publisher = "none"
+	}
+	if cfg.Outbox.HTTPTimeout <= 0 {
+		cfg.Outbox.HTTPTimeout = 10 * time.Second
+	}
+	if cfg.Outbox.PollInterval <= 0 {
+		cfg.Outbox.PollInterval = 2 * time.Second
+	}
+	if cfg.Outbox.BatchSize <= 0 {
+		cfg.Outbox.BatchSize = 100
+	}
+	if cfg.Outbox.RetryBase <= 0 {
+		cfg.Outbox.RetryBase = time.Second
+	}
+	if cfg.Outbox.RetryMax <= 0 {
+		cfg.Outbox.RetryMax = 10 * time.Minute
+	}
+	if cfg.Outbox.ClaimLease <= 0 {
+		cfg.Outbox.ClaimLease = 5 * time.Minute
+	}
+	if cfg.Webhooks.PollInterval <= 0 {
+		cfg.Webhooks.PollInterval = 2 * time.Second
+	}
+	if cfg.Webhooks.BatchSize <= 0 {
+		cfg.Webhooks.BatchSize = 50
+	}
+	if cfg.Webhooks.MaxAttempts <= 0 {
+		cfg.Webhooks.MaxAttempts = 10
+	}
+	if cfg.Webhooks.RetryBase <= 0 {
+		cfg.Webhooks.RetryBase = 5 * time.Second
+	}
+	if cfg.Webhooks.RetryMax <= 0 {
+		cfg.Webhooks.RetryMax = time.Hour
+	}
+	if cfg.Webhooks.HTTPTimeout <= 0 {
+		cfg.Webhooks.HTTPTimeout = 10 * time.Second
+	}
+	if cfg.Webhooks.ClaimLease <= 0 {
+		cfg.Webhooks.ClaimLease = 10 * time.Minute
+	}
+	if cfg.SLA.SweepInterval <= 0 {
+		cfg.SLA.SweepInterval = time.Minute
+	}
+	if cfg.SLA.BatchSize <= 0 {
+		cfg.SLA.BatchSize = 100
+	}
+	if cfg.NeedInfo.DefaultWindow <= 0 {
+		cfg.NeedInfo.DefaultWindow = 72 * time.Hour
+	}
+	if cfg.NeedInfo.Grace <= 0 {
+		cfg.NeedInfo.Grace = 24 * time.Hour
+	}
+	if cfg.NeedInfo.SweepInterval <= 0 {
+		cfg.NeedInfo.SweepInterval = 5 * time.Minute
+	}
+
+	if cfg.Tracing.Exporter == "" {
+		cfg.Tracing.Exporter = "none"
+	}
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "violation-service"
+	}
 
 	if err := validate(cfg); err != nil {
 		return nil, err
@@ -92,8 +330,71 @@ func validate(cfg *Config) error {
 	if cfg.DB.DSN == "" {
 		return fmt.Errorf("DB_DSN is required")
 	}
-	if cfg.Auth.AccessSecret == "" {
-		return fmt.Errorf("JWT_ACCESS_SECRET is required")
+	for _, alg := range cfg.Auth.Algorithms {
+		switch {
+		case !slices.Contains(supportedAlgorithms, alg):
+			return fmt.Errorf("JWT_ALGORITHMS: unsupported algorithm %q", alg)
+		case strings.HasPrefix(alg, "HS") && cfg.Auth.AccessSecret == "":
+			return fmt.Errorf("JWT_ACCESS_SECRET is required for %s", alg)
+		case !strings.HasPrefix(alg, "HS") && cfg.Auth.JWKSURL == "":
+			return fmt.Errorf("JWT_JWKS_URL is required for %s", alg)
+		}
+	}
+	switch cfg.Outbox.Publisher {
+	case "none", "stdout":
+	case "file":
+		if cfg.Outbox.FilePath == "" {
+			return fmt.Errorf("OUTBOX_FILE_PATH is required for file publisher")
+		}
+	case "http":
+		if cfg.Outbox.HTTPURL == "" {
+			return fmt.Errorf("OUTBOX_HTTP_URL is required for http publisher")
+		}
+	default:
+		return fmt.Errorf("OUTBOX_PUBLISHER must be one of none, stdout, file, http")
+	}
+	switch cfg.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	case "file":
+		if cfg.Tracing.FilePath == "" {
+			return fmt.Errorf("TRACING_FILE_PATH is required for file exporter")
+		}
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be one of none, otlp, stdout, file")
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
 	}
 	return nil
 }
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseDurationRules разбирает список вида "KEY=48h,OTHER:KEY=24h".
+func parseDurationRules(value string) (map[string]time.Duration, error) {
+	rules := make(map[string]time.Duration)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("rule %q must look like KEY=duration", item)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", item, err)
+		}
+		rules[strings.ToUpper(strings.TrimSpace(key))] = duration
+	}
+	return rules, nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,23 +12,21 @@ import (
 	gormlogger "gorm.io/gorm/logger"
 
 	"violation-service/internal/config"
+	"violation-service/internal/logger"
+	"violation-service/internal/metrics"
 )
 
-func New(cfg *config.Config, log zerolog.Logger) (*gorm.DB, error) {
+const (
+	slowQueryThreshold = time.Second
+	connectBackoffMin  = 500 * time.Millisecond
+	connectBackoffMax  = 10 * time.Second
+)
+
+func New(ctx context.Context, cfg *config.Config, log zerolog.Logger) (*gorm.DB, error) {
 	dbCfg := cfg.DB
-	gormLog := gormlogger.New(
-		zerologWriter{logger: log},
-		gormlogger.Config{
-			SlowThreshold:             time.Second,
-			Colorful:                  false,
-			IgnoreRecordNotFoundError: true,
-			LogLevel:                  selectLogLevel(cfg.Environment),
-		},
-	)
-
-	database, err := gorm.Open(postgres.Open(dbCfg.DSN), &gorm.Config{
-		Logger: gormLog,
-	})
+	gormLog := queryLogger{level: selectLogLevel(cfg.Environment)}
+
+	database, err := connect(ctx, dbCfg, gormLog, log)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +36,10 @@ func New(cfg *config.Config, log zerolog.Logger) (*gorm.DB, error) {
 		return nil, err
 	}
 
+	if err := registerTracing(database); err != nil {
+		return nil, fmt.Errorf("register tracing callbacks: %w", err)
+	}
+
 	if dbCfg.MaxOpenConns > 0 {
 		sqlDB.SetMaxOpenConns(dbCfg.MaxOpenConns)
 	}
@@ -47,13 +50,51 @@ func New(cfg *config.Config, log zerolog.Logger) (*gorm.DB, error) {
 		sqlDB.SetConnMaxLifetime(dbCfg.ConnMaxLifetime)
 	}
 
-	if err := runMigrations(database); err != nil {
-		return nil, fmt.Errorf("run migrations: %w", err)
+	// При DB_AUTO_MIGRATE=false схему обновляют отдельно командой migrate up.
+	if dbCfg.AutoMigrate {
+		migrator, err := NewMigrator(database, log)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			return nil, fmt.Errorf("run migrations: %w", err)
+		}
 	}
 
 	return database, nil
 }
 
+// connect повторяет подключение с экспоненциальной задержкой в пределах ConnectTimeout:
+// при совместном старте с Postgres база часто поднимается позже сервиса.
+func connect(ctx context.Context, dbCfg config.DBConfig, gormLog gormlogger.Interface, log zerolog.Logger) (*gorm.DB, error) {
+	deadline := time.Now().Add(dbCfg.ConnectTimeout)
+	backoff := connectBackoffMin
+	for attempt := 1; ; attempt++ {
+		database, err := gorm.Open(postgres.Open(dbCfg.DSN), &gorm.Config{
+			Logger: gormLog,
+		})
+		if err == nil {
+			return database, nil
+		}
+		if database != nil {
+			if sqlDB, dbErr := database.DB(); dbErr == nil {
+				_ = sqlDB.Close()
+			}
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("connect after %d attempts: %w", attempt, err)
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", backoff).Msg("database is not reachable, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, connectBackoffMax)
+	}
+}
+
 func HealthCheck(ctx context.Context, db *gorm.DB) error {
 	return db.WithContext(ctx).Exec("SELECT 1").Error
 }
@@ -65,10 +106,59 @@ func selectLogLevel(env string) gormlogger.LogLevel {
 	return gormlogger.Warn
 }
 
-type zerologWriter struct {
-	logger zerolog.Logger
+// queryLogger пишет запросы GORM в логгер из контекста запроса, поэтому строки SQL
+// несут тот же request_id, что и access-лог. Заодно считает медленные запросы
+// с тем же порогом, с которым они логируются.
+type queryLogger struct {
+	level gormlogger.LogLevel
+}
+
+func (l queryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return queryLogger{level: level}
+}
+
+func (l queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		logger.FromContext(ctx).Info().Msgf(msg, args...)
+	}
+}
+
+func (l queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		logger.FromContext(ctx).Warn().Msgf(msg, args...)
+	}
+}
+
+func (l queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		logger.FromContext(ctx).Error().Msgf(msg, args...)
+	}
 }
 
-func (w zerologWriter) Printf(msg string, args ...interface{}) {
-	w.logger.Info().Msgf(msg, args...)
+func (l queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	slow := elapsed > slowQueryThreshold
+	if slow {
+		metrics.SlowQuery()
+	}
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	log := logger.FromContext(ctx)
+	var event *zerolog.Event
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		event = log.Error().Err(err)
+	case slow && l.level >= gormlogger.Warn:
+		event = log.Warn().Bool("slow", true)
+	case l.level >= gormlogger.Info:
+		// Уровень gorm Info означает «логировать каждый запрос», поэтому и в zerolog это Info:
+		// с Debug запросы терялись бы при уровне логгера по умолчанию.
+		event = log.Info()
+	default:
+		return
+	}
+	sql, rows := fc()
+	event.Str("sql", sql).Int64("rows", rows).Dur("elapsed_ms", elapsed).Msg("query")
 }
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"violation-service/internal/logger"
+)
+
+func TestQueryLoggerLevels(t *testing.T) {
+	query := func() (string, int64) { return "SELECT 1", 1 }
+	recent := time.Now()
+	slow := time.Now().Add(-2 * slowQueryThreshold)
+
+	tests := []struct {
+		name      string
+		level     gormlogger.LogLevel
+		begin     time.Time
+		err       error
+		wantLevel string
+	}{
+		{name: "info logs every query at info", level: gormlogger.Info, begin: recent, wantLevel: "info"},
+		{name: "warn skips fast queries", level: gormlogger.Warn, begin: recent},
+		{name: "warn logs slow queries", level: gormlogger.Warn, begin: slow, wantLevel: "warn"},
+		{name: "error logs failures", level: gormlogger.Error, begin: recent, err: errors.New("boom"), wantLevel: "error"},
+		{name: "record not found is not an error", level: gormlogger.Error, begin: recent, err: gorm.ErrRecordNotFound},
+		{name: "silent", level: gormlogger.Silent, begin: slow, err: errors.New("boom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := logger.WithContext(context.Background(), zerolog.New(&buf).Level(zerolog.InfoLevel))
+
+			queryLogger{}.LogMode(tt.level).Trace(ctx, tt.begin, query, tt.err)
+
+			if tt.wantLevel == "" {
+				if buf.Len() != 0 {
+					t.Fatalf("unexpected log line %s", buf.String())
+				}
+				return
+			}
+			var line struct {
+				Level string `json:"level"`
+				SQL   string `json:"sql"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+				t.Fatalf("log output %q: %v", buf.String(), err)
+			}
+			if line.Level != tt.wantLevel || line.SQL != "SELECT 1" {
+				t.Errorf("logged %+v, want level %s with the SQL", line, tt.wantLevel)
+			}
+		})
+	}
+}
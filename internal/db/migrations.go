@@ -1,257 +1,543 @@
 package db
 
-import (
-	"fmt"
+// Migration — пронумерованное изменение схемы. Применённые миграции фиксируются в
+// schema_migrations вместе с контрольной суммой Up, поэтому менять их нельзя: любое
+// изменение схемы оформляется новой миграцией в конце списка.
+type Migration struct {
+	Version int
+	Name    string
+	Up      []string
+	Down    []string
+}
 
-	"gorm.io/gorm"
-)
+// AutoViolationNote — note, с которой триггер trg_trips_auto_violation пишет первую
+// запись violation_status_log и событие violation.created.
+const AutoViolationNote = "auto from trip status"
 
-var migrationStatements = []string{
-	`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`,
-	`CREATE EXTENSION IF NOT EXISTS "pgcrypto";`,
-	`DO $$
-	BEGIN
-		IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'violation_status') THEN
-			CREATE TYPE violation_status AS ENUM ('OPEN', 'CANCELED', 'FIXED');
-		END IF;
-		IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'violation_severity') THEN
-			CREATE TYPE violation_severity AS ENUM ('LOW', 'MEDIUM', 'HIGH');
-		END IF;
-		IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'violation_detected_by') THEN
-			CREATE TYPE violation_detected_by AS ENUM ('LPR', 'VOLUME', 'GPS', 'SYSTEM');
-		END IF;
-		IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'appeal_status') THEN
-			CREATE TYPE appeal_status AS ENUM ('SUBMITTED', 'UNDER_REVIEW', 'NEED_INFO', 'APPROVED', 'REJECTED', 'CLOSED');
-		END IF;
-		IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'appeal_reason_code') THEN
-			CREATE TYPE appeal_reason_code AS ENUM ('CAMERA_ERROR', 'TRANSIT_PATH', 'WRONG_ASSIGNMENT', 'OTHER');
-		END IF;
-		IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'attachment_file_type') THEN
-			CREATE TYPE attachment_file_type AS ENUM ('IMAGE', 'VIDEO', 'DOC');
-		END IF;
-	END
-	$$;`,
-	`CREATE TABLE IF NOT EXISTS violations (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
-		type VARCHAR(64) NOT NULL,
-		detected_by violation_detected_by NOT NULL,
-		severity violation_severity NOT NULL,
-		status violation_status NOT NULL DEFAULT 'OPEN',
-		description TEXT,
-		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`,
-	`CREATE INDEX IF NOT EXISTS idx_violations_trip_id ON violations (trip_id);`,
-	`CREATE INDEX IF NOT EXISTS idx_violations_status ON violations (status);`,
-	`CREATE INDEX IF NOT EXISTS idx_violations_detected_by ON violations (detected_by);`,
-	`CREATE INDEX IF NOT EXISTS idx_violations_created_at ON violations (created_at);`,
-	`DO $$
-	BEGIN
-		IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'trips' AND column_name = 'violation_reason') THEN
-			ALTER TABLE trips ADD COLUMN violation_reason TEXT;
-		END IF;
-	END
-	$$;`,
-	`CREATE TABLE IF NOT EXISTS violation_appeals (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		violation_id UUID NOT NULL REFERENCES violations(id) ON DELETE CASCADE,
-		trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
-		ticket_id UUID REFERENCES tickets(id) ON DELETE SET NULL,
-		driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
-		contractor_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
-		reason_code appeal_reason_code NOT NULL,
-		reason_text TEXT NOT NULL,
-		status appeal_status NOT NULL DEFAULT 'SUBMITTED',
-		resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
-		resolved_at TIMESTAMPTZ,
-		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`,
-	`CREATE INDEX IF NOT EXISTS idx_violation_appeals_violation_id ON violation_appeals (violation_id);`,
-	`CREATE INDEX IF NOT EXISTS idx_violation_appeals_status ON violation_appeals (status);`,
-	`CREATE INDEX IF NOT EXISTS idx_violation_appeals_reason_code ON violation_appeals (reason_code);`,
-	`CREATE UNIQUE INDEX IF NOT EXISTS uniq_violation_active_appeal
-		ON violation_appeals (violation_id)
-		WHERE status IN ('SUBMITTED', 'UNDER_REVIEW', 'NEED_INFO');`,
-	`CREATE TABLE IF NOT EXISTS violation_appeal_attachments (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		appeal_id UUID NOT NULL REFERENCES violation_appeals(id) ON DELETE CASCADE,
-		file_url TEXT NOT NULL,
-		file_type attachment_file_type NOT NULL,
-		uploaded_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`,
-	`CREATE INDEX IF NOT EXISTS idx_violation_attachments_appeal_id ON violation_appeal_attachments (appeal_id);`,
-	`CREATE TABLE IF NOT EXISTS violation_appeal_comments (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		appeal_id UUID NOT NULL REFERENCES violation_appeals(id) ON DELETE CASCADE,
-		author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-		author_role VARCHAR(32) NOT NULL,
-		message TEXT NOT NULL,
-		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`,
-	`CREATE INDEX IF NOT EXISTS idx_violation_comments_appeal_id ON violation_appeal_comments (appeal_id);`,
-	`CREATE TABLE IF NOT EXISTS violation_status_log (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		violation_id UUID NOT NULL REFERENCES violations(id) ON DELETE CASCADE,
-		old_status violation_status,
-		new_status violation_status NOT NULL,
-		note TEXT,
-		changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
-		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`,
-	`CREATE INDEX IF NOT EXISTS idx_violation_status_log_violation_id ON violation_status_log (violation_id);`,
-	`CREATE TABLE IF NOT EXISTS appeal_status_log (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		appeal_id UUID NOT NULL REFERENCES violation_appeals(id) ON DELETE CASCADE,
-		old_status appeal_status,
-		new_status appeal_status NOT NULL,
-		note TEXT,
-		changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
-		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-	);`,
-	`CREATE INDEX IF NOT EXISTS idx_appeal_status_log_appeal_id ON appeal_status_log (appeal_id);`,
-	`CREATE OR REPLACE FUNCTION set_row_updated_at()
-	RETURNS TRIGGER AS $$
-	BEGIN
-		NEW.updated_at = NOW();
-		RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;`,
-	`DO $$
-	BEGIN
-		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_violations_updated_at') THEN
-			CREATE TRIGGER trg_violations_updated_at
-				BEFORE UPDATE ON violations
-				FOR EACH ROW
-				EXECUTE PROCEDURE set_row_updated_at();
-		END IF;
-		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_violation_appeals_updated_at') THEN
-			CREATE TRIGGER trg_violation_appeals_updated_at
-				BEFORE UPDATE ON violation_appeals
-				FOR EACH ROW
-				EXECUTE PROCEDURE set_row_updated_at();
-		END IF;
-	END
-	$$;`,
-	`CREATE OR REPLACE FUNCTION map_trip_status_to_violation(status TEXT)
-	RETURNS TABLE(v_type VARCHAR, v_detected violation_detected_by, v_severity violation_severity) AS $$
-	BEGIN
-		RETURN QUERY SELECT
-			CAST(CASE status
-				WHEN 'ROUTE_VIOLATION' THEN 'ROUTE_VIOLATION'
-				WHEN 'FOREIGN_AREA' THEN 'FOREIGN_AREA'
-				WHEN 'MISMATCH_PLATE' THEN 'MISMATCH_PLATE'
-				WHEN 'OVER_CAPACITY' THEN 'OVER_CAPACITY'
-				WHEN 'NO_AREA_WORK' THEN 'NO_AREA_WORK'
-				WHEN 'NO_ASSIGNMENT' THEN 'NO_AREA_WORK'
-				WHEN 'SUSPICIOUS_VOLUME' THEN 'OVER_CAPACITY'
-				WHEN 'OVER_CONTRACT_LIMIT' THEN 'OVER_CONTRACT_LIMIT'
-				ELSE 'SYSTEM' END AS VARCHAR) AS v_type,
-			CAST(CASE status
-				WHEN 'MISMATCH_PLATE' THEN 'LPR'
-				WHEN 'ROUTE_VIOLATION' THEN 'GPS'
-				WHEN 'FOREIGN_AREA' THEN 'GPS'
-				WHEN 'SUSPICIOUS_VOLUME' THEN 'VOLUME'
-				WHEN 'OVER_CAPACITY' THEN 'VOLUME'
-				ELSE 'SYSTEM' END AS violation_detected_by) AS v_detected,
-			CAST(CASE status
-				WHEN 'ROUTE_VIOLATION' THEN 'HIGH'
-				WHEN 'FOREIGN_AREA' THEN 'HIGH'
-				WHEN 'OVER_CAPACITY' THEN 'HIGH'
-				WHEN 'SUSPICIOUS_VOLUME' THEN 'MEDIUM'
-				WHEN 'MISMATCH_PLATE' THEN 'MEDIUM'
-				WHEN 'NO_ASSIGNMENT' THEN 'MEDIUM'
-				WHEN 'NO_AREA_WORK' THEN 'MEDIUM'
-				ELSE 'LOW' END AS violation_severity) AS v_severity;
-	END;
-	$$ LANGUAGE plpgsql;`,
-	`CREATE OR REPLACE FUNCTION trg_trips_set_violation_reason()
-	RETURNS TRIGGER AS $$
-	BEGIN
-		IF NEW.status <> 'OK'
-			AND (OLD.status IS NULL OR OLD.status = 'OK')
-			AND (NEW.violation_reason IS NULL OR NEW.violation_reason = '') THEN
-			NEW.violation_reason := CONCAT('Auto violation: ', NEW.status);
-		END IF;
-		RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;`,
-	`DO $$
-	BEGIN
-		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_trips_set_violation_reason') THEN
-			CREATE TRIGGER trg_trips_set_violation_reason
-				BEFORE UPDATE OF status ON trips
-				FOR EACH ROW
-				WHEN (NEW.status <> 'OK' AND (OLD.status IS NULL OR OLD.status = 'OK'))
-				EXECUTE PROCEDURE trg_trips_set_violation_reason();
-		END IF;
-	END
-	$$;`,
-	`CREATE OR REPLACE FUNCTION trg_trips_auto_violation()
-	RETURNS TRIGGER AS $$
-	DECLARE
-		v_type VARCHAR;
-		v_detected violation_detected_by;
-		v_severity violation_severity;
-		v_description TEXT;
-		v_existing UUID;
-		v_new_id UUID;
-	BEGIN
-		IF NEW.status = 'OK' THEN
-			RETURN NEW;
-		END IF;
-		IF TG_OP = 'UPDATE' AND (OLD.status = NEW.status) THEN
-			RETURN NEW;
-		END IF;
-		SELECT result.v_type, result.v_detected, result.v_severity
-			INTO v_type, v_detected, v_severity
-		FROM map_trip_status_to_violation(NEW.status) AS result;
+// migrations 1–10 идемпотентны: базы, развёрнутые до появления schema_migrations,
+// принимают их повторно без изменений и просто получают записи о версиях.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: []string{
+			`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`,
+			`CREATE EXTENSION IF NOT EXISTS "pgcrypto";`,
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'violation_status') THEN
+					CREATE TYPE violation_status AS ENUM ('OPEN', 'CANCELED', 'FIXED');
+				END IF;
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'violation_severity') THEN
+					CREATE TYPE violation_severity AS ENUM ('LOW', 'MEDIUM', 'HIGH');
+				END IF;
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'violation_detected_by') THEN
+					CREATE TYPE violation_detected_by AS ENUM ('LPR', 'VOLUME', 'GPS', 'SYSTEM');
+				END IF;
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'appeal_status') THEN
+					CREATE TYPE appeal_status AS ENUM ('SUBMITTED', 'UNDER_REVIEW', 'NEED_INFO', 'APPROVED', 'REJECTED', 'CLOSED');
+				END IF;
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'appeal_reason_code') THEN
+					CREATE TYPE appeal_reason_code AS ENUM ('CAMERA_ERROR', 'TRANSIT_PATH', 'WRONG_ASSIGNMENT', 'OTHER');
+				END IF;
+				IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'attachment_file_type') THEN
+					CREATE TYPE attachment_file_type AS ENUM ('IMAGE', 'VIDEO', 'DOC');
+				END IF;
+			END
+			$$;`,
+			`CREATE TABLE IF NOT EXISTS violations (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+				type VARCHAR(64) NOT NULL,
+				detected_by violation_detected_by NOT NULL,
+				severity violation_severity NOT NULL,
+				status violation_status NOT NULL DEFAULT 'OPEN',
+				description TEXT,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_violations_trip_id ON violations (trip_id);`,
+			`CREATE INDEX IF NOT EXISTS idx_violations_status ON violations (status);`,
+			`CREATE INDEX IF NOT EXISTS idx_violations_detected_by ON violations (detected_by);`,
+			`CREATE INDEX IF NOT EXISTS idx_violations_created_at ON violations (created_at);`,
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'trips' AND column_name = 'violation_reason') THEN
+					ALTER TABLE trips ADD COLUMN violation_reason TEXT;
+				END IF;
+			END
+			$$;`,
+			`CREATE TABLE IF NOT EXISTS violation_appeals (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				violation_id UUID NOT NULL REFERENCES violations(id) ON DELETE CASCADE,
+				trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+				ticket_id UUID REFERENCES tickets(id) ON DELETE SET NULL,
+				driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+				contractor_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+				reason_code appeal_reason_code NOT NULL,
+				reason_text TEXT NOT NULL,
+				status appeal_status NOT NULL DEFAULT 'SUBMITTED',
+				resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				resolved_at TIMESTAMPTZ,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_appeals_violation_id ON violation_appeals (violation_id);`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_appeals_status ON violation_appeals (status);`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_appeals_reason_code ON violation_appeals (reason_code);`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS uniq_violation_active_appeal
+				ON violation_appeals (violation_id)
+				WHERE status IN ('SUBMITTED', 'UNDER_REVIEW', 'NEED_INFO');`,
+			`CREATE TABLE IF NOT EXISTS violation_appeal_attachments (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				appeal_id UUID NOT NULL REFERENCES violation_appeals(id) ON DELETE CASCADE,
+				file_url TEXT NOT NULL,
+				file_type attachment_file_type NOT NULL,
+				uploaded_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_attachments_appeal_id ON violation_appeal_attachments (appeal_id);`,
+			`CREATE TABLE IF NOT EXISTS violation_appeal_comments (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				appeal_id UUID NOT NULL REFERENCES violation_appeals(id) ON DELETE CASCADE,
+				author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				author_role VARCHAR(32) NOT NULL,
+				message TEXT NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_comments_appeal_id ON violation_appeal_comments (appeal_id);`,
+			`CREATE TABLE IF NOT EXISTS violation_status_log (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				violation_id UUID NOT NULL REFERENCES violations(id) ON DELETE CASCADE,
+				old_status violation_status,
+				new_status violation_status NOT NULL,
+				note TEXT,
+				changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_status_log_violation_id ON violation_status_log (violation_id);`,
+			`CREATE TABLE IF NOT EXISTS appeal_status_log (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				appeal_id UUID NOT NULL REFERENCES violation_appeals(id) ON DELETE CASCADE,
+				old_status appeal_status,
+				new_status appeal_status NOT NULL,
+				note TEXT,
+				changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_appeal_status_log_appeal_id ON appeal_status_log (appeal_id);`,
+			`CREATE OR REPLACE FUNCTION set_row_updated_at()
+			RETURNS TRIGGER AS $$
+			BEGIN
+				NEW.updated_at = NOW();
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql;`,
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_violations_updated_at') THEN
+					CREATE TRIGGER trg_violations_updated_at
+						BEFORE UPDATE ON violations
+						FOR EACH ROW
+						EXECUTE PROCEDURE set_row_updated_at();
+				END IF;
+				IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_violation_appeals_updated_at') THEN
+					CREATE TRIGGER trg_violation_appeals_updated_at
+						BEFORE UPDATE ON violation_appeals
+						FOR EACH ROW
+						EXECUTE PROCEDURE set_row_updated_at();
+				END IF;
+			END
+			$$;`,
+		},
+		// Расширения и колонку trips.violation_reason не удаляем: ими пользуются другие сервисы.
+		Down: []string{
+			`DROP TABLE IF EXISTS appeal_status_log, violation_status_log, violation_appeal_comments, violation_appeal_attachments, violation_appeals, violations;`,
+			`DROP FUNCTION IF EXISTS set_row_updated_at();`,
+			`DROP TYPE IF EXISTS attachment_file_type, appeal_reason_code, appeal_status, violation_detected_by, violation_severity, violation_status;`,
+		},
+	},
+	{
+		Version: 2,
+		Name:    "optimistic_locking",
+		Up: []string{
+			`ALTER TABLE violations ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;`,
+			`ALTER TABLE violation_appeals ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;`,
+		},
+		Down: []string{
+			`ALTER TABLE violation_appeals DROP COLUMN IF EXISTS version;`,
+			`ALTER TABLE violations DROP COLUMN IF EXISTS version;`,
+		},
+	},
+	{
+		Version: 3,
+		Name:    "appeal_sla",
+		Up: []string{
+			`ALTER TABLE violation_appeals ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;`,
+			`ALTER TABLE violation_appeals ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMPTZ;`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_appeals_due_at
+				ON violation_appeals (due_at)
+				WHERE status IN ('SUBMITTED', 'UNDER_REVIEW', 'NEED_INFO');`,
+		},
+		Down: []string{
+			`DROP INDEX IF EXISTS idx_violation_appeals_due_at;`,
+			`ALTER TABLE violation_appeals DROP COLUMN IF EXISTS overdue_at, DROP COLUMN IF EXISTS due_at;`,
+		},
+	},
+	{
+		Version: 4,
+		Name:    "need_info_reminders",
+		Up: []string{
+			`ALTER TABLE violation_appeals ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;`,
+			`UPDATE violation_appeals SET status_changed_at = updated_at WHERE status_changed_at IS NULL;`,
+			`ALTER TABLE violation_appeals ALTER COLUMN status_changed_at SET DEFAULT NOW();`,
+			`ALTER TABLE violation_appeals ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ;`,
+			`CREATE INDEX IF NOT EXISTS idx_violation_appeals_need_info
+				ON violation_appeals (status_changed_at)
+				WHERE status = 'NEED_INFO';`,
+			`CREATE TABLE IF NOT EXISTS need_info_policies (
+				organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+				window_hours INTEGER NOT NULL CHECK (window_hours > 0),
+				updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS need_info_policies;`,
+			`DROP INDEX IF EXISTS idx_violation_appeals_need_info;`,
+			`ALTER TABLE violation_appeals DROP COLUMN IF EXISTS reminded_at, DROP COLUMN IF EXISTS status_changed_at;`,
+		},
+	},
+	{
+		Version: 5,
+		Name:    "outbox_events",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS outbox_events (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				aggregate_type VARCHAR(32) NOT NULL,
+				aggregate_id UUID NOT NULL,
+				event_type VARCHAR(64) NOT NULL,
+				payload JSONB NOT NULL,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				last_error TEXT,
+				published_at TIMESTAMPTZ,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_outbox_events_pending
+				ON outbox_events (next_attempt_at, created_at)
+				WHERE published_at IS NULL;`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS outbox_events;`,
+		},
+	},
+	{
+		Version: 6,
+		Name:    "webhooks",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+				owner_role VARCHAR(32) NOT NULL,
+				url TEXT NOT NULL,
+				secret TEXT NOT NULL,
+				event_types JSONB NOT NULL DEFAULT '[]'::jsonb,
+				is_active BOOLEAN NOT NULL DEFAULT TRUE,
+				created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_org ON webhook_subscriptions (organization_id);`,
+			`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				subscription_id UUID NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+				event_id UUID NOT NULL,
+				event_type VARCHAR(64) NOT NULL,
+				payload JSONB NOT NULL,
+				status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+				attempts INTEGER NOT NULL DEFAULT 0,
+				next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				last_status_code INTEGER,
+				last_error TEXT,
+				delivered_at TIMESTAMPTZ,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS uniq_webhook_delivery_event ON webhook_deliveries (subscription_id, event_id);`,
+			`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending
+				ON webhook_deliveries (next_attempt_at)
+				WHERE status = 'PENDING';`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS webhook_deliveries, webhook_subscriptions;`,
+		},
+	},
+	{
+		Version: 7,
+		Name:    "violation_types",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS violation_types (
+				code VARCHAR(64) PRIMARY KEY,
+				names JSONB NOT NULL DEFAULT '{}'::jsonb,
+				default_severity violation_severity NOT NULL DEFAULT 'MEDIUM',
+				detection_sources JSONB NOT NULL DEFAULT '[]'::jsonb,
+				appealable BOOLEAN NOT NULL DEFAULT TRUE,
+				is_active BOOLEAN NOT NULL DEFAULT TRUE,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`INSERT INTO violation_types (code, names, default_severity, detection_sources) VALUES
+				('ROUTE_VIOLATION', '{"ru": "Отклонение от маршрута", "en": "Route violation"}', 'HIGH', '["GPS"]'),
+				('FOREIGN_AREA', '{"ru": "Работа на чужом участке", "en": "Foreign area"}', 'HIGH', '["GPS"]'),
+				('MISMATCH_PLATE', '{"ru": "Несовпадение госномера", "en": "Plate mismatch"}', 'MEDIUM', '["LPR"]'),
+				('OVER_CAPACITY', '{"ru": "Превышение объёма кузова", "en": "Over capacity"}', 'HIGH', '["VOLUME"]'),
+				('NO_AREA_WORK', '{"ru": "Нет работы на участке", "en": "No area work"}', 'MEDIUM', '[]'),
+				('OVER_CONTRACT_LIMIT', '{"ru": "Превышение лимита договора", "en": "Over contract limit"}', 'LOW', '[]'),
+				('SYSTEM', '{"ru": "Системное подозрение", "en": "System suspicion"}', 'LOW', '[]')
+			ON CONFLICT (code) DO NOTHING;`,
+			// Типы, уже встречающиеся в данных, попадают в справочник до появления внешнего ключа.
+			`INSERT INTO violation_types (code, names)
+				SELECT DISTINCT type, jsonb_build_object('ru', type) FROM violations
+			ON CONFLICT (code) DO NOTHING;`,
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_violations_type') THEN
+					ALTER TABLE violations
+						ADD CONSTRAINT fk_violations_type FOREIGN KEY (type)
+						REFERENCES violation_types(code);
+				END IF;
+			END
+			$$;`,
+		},
+		Down: []string{
+			`ALTER TABLE violations DROP CONSTRAINT IF EXISTS fk_violations_type;`,
+			`DROP TABLE IF EXISTS violation_types;`,
+		},
+	},
+	{
+		Version: 8,
+		Name:    "session_revocations",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS revoked_sessions (
+				session_id UUID PRIMARY KEY,
+				reason TEXT,
+				revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE TABLE IF NOT EXISTS user_session_revocations (
+				user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+				revoked_before TIMESTAMPTZ NOT NULL,
+				reason TEXT,
+				revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+		},
+		Down: []string{
+			`DROP TABLE IF EXISTS user_session_revocations, revoked_sessions;`,
+		},
+	},
+	{
+		Version: 9,
+		Name:    "trip_status_rules",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS trip_status_violation_rules (
+				trip_status VARCHAR(64) PRIMARY KEY,
+				violation_type VARCHAR(64) NOT NULL REFERENCES violation_types(code),
+				detected_by violation_detected_by NOT NULL,
+				severity violation_severity NOT NULL,
+				enabled BOOLEAN NOT NULL DEFAULT TRUE,
+				updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			// Правило '*' применяется к статусам без собственного правила.
+			`INSERT INTO trip_status_violation_rules (trip_status, violation_type, detected_by, severity) VALUES
+				('ROUTE_VIOLATION', 'ROUTE_VIOLATION', 'GPS', 'HIGH'),
+				('FOREIGN_AREA', 'FOREIGN_AREA', 'GPS', 'HIGH'),
+				('MISMATCH_PLATE', 'MISMATCH_PLATE', 'LPR', 'MEDIUM'),
+				('OVER_CAPACITY', 'OVER_CAPACITY', 'VOLUME', 'HIGH'),
+				('SUSPICIOUS_VOLUME', 'OVER_CAPACITY', 'VOLUME', 'MEDIUM'),
+				('NO_AREA_WORK', 'NO_AREA_WORK', 'SYSTEM', 'MEDIUM'),
+				('NO_ASSIGNMENT', 'NO_AREA_WORK', 'SYSTEM', 'MEDIUM'),
+				('OVER_CONTRACT_LIMIT', 'OVER_CONTRACT_LIMIT', 'SYSTEM', 'LOW'),
+				('*', 'SYSTEM', 'SYSTEM', 'LOW')
+			ON CONFLICT (trip_status) DO NOTHING;`,
+			`CREATE TABLE IF NOT EXISTS trip_status_violation_rule_log (
+				id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+				trip_status VARCHAR(64) NOT NULL,
+				action VARCHAR(16) NOT NULL,
+				old_rule JSONB,
+				new_rule JSONB,
+				changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+				created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+			);`,
+			`CREATE INDEX IF NOT EXISTS idx_trip_status_violation_rule_log_status
+				ON trip_status_violation_rule_log (trip_status, created_at DESC);`,
+			// Собственное правило статуса важнее '*', даже если оно выключено: выключенное правило
+			// означает «не создавать нарушение», а не «взять правило по умолчанию».
+			`CREATE OR REPLACE FUNCTION map_trip_status_to_violation(status TEXT)
+			RETURNS TABLE(v_type VARCHAR, v_detected violation_detected_by, v_severity violation_severity) AS $$
+			BEGIN
+				RETURN QUERY
+				SELECT m.violation_type, m.detected_by, m.severity
+				FROM (
+					SELECT r.violation_type, r.detected_by, r.severity, r.enabled
+					FROM trip_status_violation_rules r
+					WHERE r.trip_status IN (status, '*')
+					ORDER BY (r.trip_status = '*')
+					LIMIT 1
+				) m
+				WHERE m.enabled;
+			END;
+			$$ LANGUAGE plpgsql STABLE;`,
+		},
+		Down: []string{
+			`DROP FUNCTION IF EXISTS map_trip_status_to_violation(TEXT);`,
+			`DROP TABLE IF EXISTS trip_status_violation_rule_log, trip_status_violation_rules;`,
+		},
+	},
+	{
+		Version: 10,
+		Name:    "trip_triggers",
+		Up: []string{
+			`CREATE OR REPLACE FUNCTION trg_trips_set_violation_reason()
+			RETURNS TRIGGER AS $$
+			BEGIN
+				IF NEW.status <> 'OK'
+					AND (OLD.status IS NULL OR OLD.status = 'OK')
+					AND (NEW.violation_reason IS NULL OR NEW.violation_reason = '')
+					AND EXISTS (SELECT 1 FROM map_trip_status_to_violation(NEW.status)) THEN
+					NEW.violation_reason := CONCAT('Auto violation: ', NEW.status);
+				END IF;
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql;`,
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_trips_set_violation_reason') THEN
+					CREATE TRIGGER trg_trips_set_violation_reason
+						BEFORE UPDATE OF status ON trips
+						FOR EACH ROW
+						WHEN (NEW.status <> 'OK' AND (OLD.status IS NULL OR OLD.status = 'OK'))
+						EXECUTE PROCEDURE trg_trips_set_violation_reason();
+				END IF;
+			END
+			$$;`,
+			autoViolationFunction(""),
+			`DO $$
+			BEGIN
+				IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_trips_auto_violation') THEN
+					CREATE TRIGGER trg_trips_auto_violation
+						AFTER UPDATE OF status ON trips
+						FOR EACH ROW
+						WHEN (NEW.status <> 'OK')
+						EXECUTE PROCEDURE trg_trips_auto_violation();
+				END IF;
+			END
+			$$;`,
+		},
+		Down: []string{
+			`DROP TRIGGER IF EXISTS trg_trips_auto_violation ON trips;`,
+			`DROP TRIGGER IF EXISTS trg_trips_set_violation_reason ON trips;`,
+			`DROP FUNCTION IF EXISTS trg_trips_auto_violation();`,
+			`DROP FUNCTION IF EXISTS trg_trips_set_violation_reason();`,
+		},
+	},
+	{
+		Version: 11,
+		Name:    "violation_auto_created_counters",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS violation_auto_created_counters (
+				type VARCHAR(64) NOT NULL,
+				detected_by violation_detected_by NOT NULL,
+				count BIGINT NOT NULL DEFAULT 0,
+				PRIMARY KEY (type, detected_by)
+			);`,
+			`INSERT INTO violation_auto_created_counters (type, detected_by, count)
+			SELECT v.type, v.detected_by, COUNT(*)
+			FROM violation_status_log l
+			JOIN violations v ON v.id = l.violation_id
+			WHERE l.old_status IS NULL AND l.changed_by IS NULL AND l.note = '` + AutoViolationNote + `'
+			GROUP BY v.type, v.detected_by
+			ON CONFLICT (type, detected_by) DO NOTHING;`,
+			autoViolationFunction(`
+				INSERT INTO violation_auto_created_counters (type, detected_by, count)
+				VALUES (v_type, v_detected, 1)
+				ON CONFLICT (type, detected_by) DO UPDATE
+					SET count = violation_auto_created_counters.count + 1;
+			`),
+		},
+		Down: []string{
+			autoViolationFunction(""),
+			`DROP TABLE IF EXISTS violation_auto_created_counters;`,
+		},
+	},
+}
 
-		IF v_type IS NULL THEN
-			RETURN NEW;
-		END IF;
+// autoViolationFunction собирает trg_trips_auto_violation; extra выполняется после
+// вставки нарушения, его журнала и события, когда v_type и v_detected уже известны.
+// Текст входит в контрольную сумму миграции 10: новое поведение триггера оформляется
+// новой миграцией со своим extra, а не правкой этой функции.
+func autoViolationFunction(extra string) string {
+	return `CREATE OR REPLACE FUNCTION trg_trips_auto_violation()
+			RETURNS TRIGGER AS $$
+			DECLARE
+				v_type VARCHAR;
+				v_detected violation_detected_by;
+				v_severity violation_severity;
+				v_description TEXT;
+				v_existing UUID;
+				v_new_id UUID;
+			BEGIN
+				IF NEW.status = 'OK' THEN
+					RETURN NEW;
+				END IF;
+				IF TG_OP = 'UPDATE' AND (OLD.status = NEW.status) THEN
+					RETURN NEW;
+				END IF;
+				SELECT result.v_type, result.v_detected, result.v_severity
+					INTO v_type, v_detected, v_severity
+				FROM map_trip_status_to_violation(NEW.status) AS result;
 
-		v_description := COALESCE(NEW.violation_reason, CONCAT('Auto violation: ', NEW.status));
+				IF v_type IS NULL THEN
+					RETURN NEW;
+				END IF;
 
-		SELECT id INTO v_existing
-		FROM violations
-		WHERE trip_id = NEW.id AND type = v_type AND status = 'OPEN'
-		LIMIT 1;
+				v_description := COALESCE(NEW.violation_reason, CONCAT('Auto violation: ', NEW.status));
 
-		IF v_existing IS NOT NULL THEN
-			RETURN NEW;
-		END IF;
+				SELECT id INTO v_existing
+				FROM violations
+				WHERE trip_id = NEW.id AND type = v_type AND status = 'OPEN'
+				LIMIT 1;
 
-		INSERT INTO violations (trip_id, type, detected_by, severity, status, description, created_at, updated_at)
-		VALUES (NEW.id, v_type, v_detected, v_severity, 'OPEN', v_description, NOW(), NOW())
-		RETURNING id INTO v_new_id;
+				IF v_existing IS NOT NULL THEN
+					RETURN NEW;
+				END IF;
 
-		INSERT INTO violation_status_log (violation_id, old_status, new_status, note, changed_by, created_at)
-		VALUES (v_new_id, NULL, 'OPEN', 'auto from trip status', NULL, NOW());
+				INSERT INTO violations (trip_id, type, detected_by, severity, status, description, created_at, updated_at)
+				VALUES (NEW.id, v_type, v_detected, v_severity, 'OPEN', v_description, NOW(), NOW())
+				RETURNING id INTO v_new_id;
 
-		RETURN NEW;
-	END;
-	$$ LANGUAGE plpgsql;`,
-	`DO $$
-	BEGIN
-		IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'trg_trips_auto_violation') THEN
-			CREATE TRIGGER trg_trips_auto_violation
-				AFTER UPDATE OF status ON trips
-				FOR EACH ROW
-				WHEN (NEW.status <> 'OK')
-				EXECUTE PROCEDURE trg_trips_auto_violation();
-		END IF;
-	END
-	$$;`,
-}
+				INSERT INTO violation_status_log (violation_id, old_status, new_status, note, changed_by, created_at)
+				VALUES (v_new_id, NULL, 'OPEN', '` + AutoViolationNote + `', NULL, NOW());
 
-func runMigrations(db *gorm.DB) error {
-	for i, stmt := range migrationStatements {
-		if err := db.Exec(stmt).Error; err != nil {
-			return fmt.Errorf("migration %d failed: %w", i+1, err)
-		}
-	}
-	return nil
+				INSERT INTO outbox_events (aggregate_type, aggregate_id, event_type, payload)
+				VALUES ('violation', v_new_id, 'violation.created', jsonb_strip_nulls(jsonb_build_object(
+					'violation_id', v_new_id,
+					'trip_id', NEW.id,
+					'contractor_id', (SELECT contractor_id FROM tickets WHERE id = NEW.ticket_id),
+					'driver_id', NEW.driver_id,
+					'polygon_id', NEW.polygon_id,
+					'violation_type', v_type,
+					'detected_by', v_detected,
+					'severity', v_severity,
+					'status', 'OPEN',
+					'note', '` + AutoViolationNote + `'
+				)));
+` + extra + `
+				RETURN NEW;
+			END;
+			$$ LANGUAGE plpgsql;`
 }
@@ -0,0 +1,330 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+// migrationLockKey — ключ pg_advisory_lock («violatio» в ASCII), общий для всех реплик сервиса.
+const migrationLockKey int64 = 0x76696f6c6174696f
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name VARCHAR(128) NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);`
+
+var (
+	// ErrChecksumMismatch означает, что применённая миграция была изменена в коде.
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrMigrationsPending — в базе применены не все миграции этой сборки.
+	ErrMigrationsPending = errors.New("migrations pending")
+)
+
+// MigrationState — состояние миграции в выводе status.
+type MigrationState string
+
+const (
+	MigrationApplied  MigrationState = "applied"
+	MigrationPending  MigrationState = "pending"
+	MigrationModified MigrationState = "modified"
+	// MigrationUnknown — версия есть в базе, но не в этой сборке (база новее кода).
+	MigrationUnknown MigrationState = "unknown"
+)
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	State     MigrationState
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator применяет и откатывает migrations. Все операции выполняются на одном соединении
+// под advisory lock, поэтому при одновременном старте реплик мигрирует только одна,
+// а остальные дожидаются её и видят уже применённые версии.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	log        zerolog.Logger
+}
+
+func NewMigrator(database *gorm.DB, log zerolog.Logger) (*Migrator, error) {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: sqlDB, migrations: migrations, log: log}, nil
+}
+
+// Up применяет все неприменённые миграции по порядку и возвращает их число.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+		for version := range applied {
+			if m.find(version) == nil {
+				m.log.Warn().Int("version", version).Msg("database has a migration unknown to this build")
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down откатывает steps последних применённых миграций и возвращает их число.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("steps must be positive")
+	}
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+		// Откат старой версии из-под более новой сломал бы схему, которую ждёт новый код.
+		for version := range applied {
+			if m.find(version) == nil {
+				return fmt.Errorf("database has migration %d unknown to this build, roll back with a newer build", version)
+			}
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status перечисляет миграции сборки и версии, известные только базе.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: MigrationPending}
+		if row, ok := applied[migration.Version]; ok {
+			status.State = MigrationApplied
+			if row.checksum != migration.checksum() {
+				status.State = MigrationModified
+			}
+			appliedAt := row.appliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, migration.Version)
+		}
+		result = append(result, status)
+	}
+	for version, row := range applied {
+		appliedAt := row.appliedAt
+		result = append(result, MigrationStatus{Version: version, Name: row.name, State: MigrationUnknown, AppliedAt: &appliedAt})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Check без блокировок и DDL проверяет, что все миграции сборки применены и не изменены.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := loadApplied(ctx, m.db)
+	if err != nil {
+		return err
+	}
+	if err := m.verify(applied); err != nil {
+		return err
+	}
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d", ErrMigrationsPending, pending)
+	}
+	return nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Отдельный контекст: блокировку нужно снять и после отмены ctx.
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(unlockCtx, "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			m.log.Error().Err(err).Msg("failed to release migration lock")
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createSchemaMigrations); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		for i, stmt := range migration.Up {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("statement %d: %w", i+1, err)
+			}
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+			migration.Version, migration.Name, migration.checksum())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	m.log.Info().Int("version", migration.Version).Str("name", migration.Name).Msg("migration applied")
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if len(migration.Down) == 0 {
+		return fmt.Errorf("migration %d %s is irreversible", migration.Version, migration.Name)
+	}
+	err := inTx(ctx, conn, func(tx *sql.Tx) error {
+		for i, stmt := range migration.Down {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("statement %d: %w", i+1, err)
+			}
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("revert migration %d %s: %w", migration.Version, migration.Name, err)
+	}
+	m.log.Info().Int("version", migration.Version).Str("name", migration.Name).Msg("migration reverted")
+	return nil
+}
+
+// verify отказывается работать, если применённую миграцию изменили после релиза.
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		if ok && row.checksum != migration.checksum() {
+			return fmt.Errorf("%w: version %d %s", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) find(version int) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// checksum считается по Up с нормализованными пробелами: переформатирование SQL
+// не делает миграцию «изменённой», а правка текста — делает.
+func (m Migration) checksum() string {
+	hash := sha256.New()
+	for _, stmt := range m.Up {
+		hash.Write([]byte(strings.Join(strings.Fields(stmt), " ")))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func loadApplied(ctx context.Context, conn queryer) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var row appliedMigration
+		if err := rows.Scan(&version, &row.name, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+	return applied, rows.Err()
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
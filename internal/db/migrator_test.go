@@ -0,0 +1,397 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestChecksumIgnoresWhitespace(t *testing.T) {
+	original := Migration{Up: []string{"CREATE TABLE t (\n\tid INT PRIMARY KEY\n);", "CREATE INDEX i ON t (id);"}}
+	reformatted := Migration{Up: []string{"  CREATE TABLE t ( id   INT\n PRIMARY KEY );", "CREATE INDEX i\n\tON t (id);\n"}}
+
+	if original.checksum() != reformatted.checksum() {
+		t.Fatal("whitespace-only change altered the checksum")
+	}
+}
+
+func TestChecksumDetectsChanges(t *testing.T) {
+	base := Migration{Up: []string{"CREATE TABLE t (id INT);", "CREATE INDEX i ON t (id);"}}
+
+	tests := []struct {
+		name string
+		up   []string
+	}{
+		{name: "changed SQL", up: []string{"CREATE TABLE t (id BIGINT);", "CREATE INDEX i ON t (id);"}},
+		{name: "changed case", up: []string{"create table t (id INT);", "CREATE INDEX i ON t (id);"}},
+		{name: "added statement", up: []string{"CREATE TABLE t (id INT);", "CREATE INDEX i ON t (id);", "SELECT 1;"}},
+		{name: "statements merged", up: []string{"CREATE TABLE t (id INT); CREATE INDEX i ON t (id);"}},
+		{name: "statements reordered", up: []string{"CREATE INDEX i ON t (id);", "CREATE TABLE t (id INT);"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (Migration{Up: tt.up}).checksum() == base.checksum() {
+				t.Fatal("checksum did not change")
+			}
+		})
+	}
+}
+
+func TestMigrationsAreOrderedAndUnique(t *testing.T) {
+	seen := make(map[int]bool, len(migrations))
+	for i, migration := range migrations {
+		if seen[migration.Version] {
+			t.Fatalf("duplicate migration version %d", migration.Version)
+		}
+		seen[migration.Version] = true
+		if i > 0 && migration.Version <= migrations[i-1].Version {
+			t.Fatalf("migration %d is listed after %d", migration.Version, migrations[i-1].Version)
+		}
+		if migration.Name == "" || len(migration.Up) == 0 {
+			t.Fatalf("migration %d has no name or statements", migration.Version)
+		}
+	}
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_a", Up: []string{"CREATE TABLE a ();"}, Down: []string{"DROP TABLE a;"}},
+	{Version: 2, Name: "create_b", Up: []string{"CREATE TABLE b ();"}, Down: []string{"DROP TABLE b;"}},
+	{Version: 3, Name: "create_c", Up: []string{"CREATE TABLE c ();"}, Down: []string{"DROP TABLE c;"}},
+}
+
+func newTestMigrator(t *testing.T, list []Migration) (*Migrator, *fakeDB) {
+	t.Helper()
+	fake, sqlDB := openFakeDB(t)
+	return &Migrator{db: sqlDB, migrations: list, log: zerolog.Nop()}, fake
+}
+
+func TestMigratorUpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	m, fake := newTestMigrator(t, testMigrations)
+
+	if err := m.Check(ctx); !errors.Is(err, ErrMigrationsPending) {
+		t.Fatalf("Check on empty database = %v, want ErrMigrationsPending", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if applied != 3 {
+		t.Fatalf("Up applied %d migrations, want 3", applied)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check after Up: %v", err)
+	}
+	if applied, err := m.Up(ctx); err != nil || applied != 0 {
+		t.Fatalf("second Up = %d, %v; want 0, nil", applied, err)
+	}
+	if got := fake.executed("CREATE TABLE"); got != 3 {
+		t.Fatalf("executed %d CREATE statements, want 3", got)
+	}
+	if fake.lockHeld() {
+		t.Fatal("advisory lock was not released")
+	}
+
+	reverted, err := m.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if reverted != 2 {
+		t.Fatalf("Down reverted %d migrations, want 2", reverted)
+	}
+	if !fake.hasVersion(1) || fake.hasVersion(2) || fake.hasVersion(3) {
+		t.Fatalf("versions after Down = %v, want [1]", fake.versions())
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	want := []MigrationState{MigrationApplied, MigrationPending, MigrationPending}
+	if len(statuses) != len(want) {
+		t.Fatalf("Status returned %d rows, want %d", len(statuses), len(want))
+	}
+	for i, status := range statuses {
+		if status.State != want[i] {
+			t.Errorf("migration %d state = %s, want %s", status.Version, status.State, want[i])
+		}
+	}
+	if err := m.Check(ctx); !errors.Is(err, ErrMigrationsPending) {
+		t.Fatalf("Check after Down = %v, want ErrMigrationsPending", err)
+	}
+}
+
+func TestMigratorChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	m, fake := newTestMigrator(t, testMigrations)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	edited := append([]Migration(nil), testMigrations...)
+	edited[1].Up = []string{"CREATE TABLE b (id INT);"}
+	m.migrations = edited
+
+	if err := m.Check(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Check = %v, want ErrChecksumMismatch", err)
+	}
+	if _, err := m.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Up = %v, want ErrChecksumMismatch", err)
+	}
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Down = %v, want ErrChecksumMismatch", err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if statuses[1].State != MigrationModified {
+		t.Fatalf("edited migration state = %s, want %s", statuses[1].State, MigrationModified)
+	}
+
+	reformatted := append([]Migration(nil), testMigrations...)
+	reformatted[1].Up = []string{"CREATE   TABLE b\n();"}
+	m.migrations = reformatted
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check after reformatting = %v, want nil", err)
+	}
+	if fake.lockHeld() {
+		t.Fatal("advisory lock was not released")
+	}
+}
+
+func TestMigratorUnknownVersion(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMigrator(t, testMigrations)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	// Старая сборка видит версию 3, о которой не знает.
+	m.migrations = testMigrations[:2]
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check = %v, want nil", err)
+	}
+	if _, err := m.Down(ctx, 1); err == nil || !strings.Contains(err.Error(), "unknown to this build") {
+		t.Fatalf("Down = %v, want unknown version error", err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if last := statuses[len(statuses)-1]; last.Version != 3 || last.State != MigrationUnknown {
+		t.Fatalf("last status = %+v, want version 3 unknown", last)
+	}
+}
+
+func TestMigratorFailedMigrationRollsBack(t *testing.T) {
+	ctx := context.Background()
+	broken := append([]Migration(nil), testMigrations...)
+	broken[1].Up = []string{"CREATE TABLE b ();", "FAIL"}
+	m, fake := newTestMigrator(t, broken)
+
+	applied, err := m.Up(ctx)
+	if err == nil || !strings.Contains(err.Error(), "migration 2 create_b") {
+		t.Fatalf("Up = %v, want migration 2 error", err)
+	}
+	if applied != 1 {
+		t.Fatalf("Up applied %d migrations before failing, want 1", applied)
+	}
+	if !fake.hasVersion(1) || fake.hasVersion(2) {
+		t.Fatalf("versions after failed Up = %v, want [1]", fake.versions())
+	}
+	if fake.lockHeld() {
+		t.Fatal("advisory lock was not released")
+	}
+}
+
+func TestMigratorDownRejectsIrreversible(t *testing.T) {
+	ctx := context.Background()
+	list := append([]Migration(nil), testMigrations...)
+	list[2].Down = nil
+	m, fake := newTestMigrator(t, list)
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	if _, err := m.Down(ctx, 0); err == nil {
+		t.Fatal("Down(0) succeeded")
+	}
+	if _, err := m.Down(ctx, 1); err == nil || !strings.Contains(err.Error(), "irreversible") {
+		t.Fatalf("Down = %v, want irreversible error", err)
+	}
+	if !fake.hasVersion(3) {
+		t.Fatal("irreversible migration was removed from schema_migrations")
+	}
+}
+
+// fakeDB — минимальный драйвер database/sql: хранит schema_migrations в памяти,
+// понимает advisory lock и транзакции, а остальной SQL только записывает.
+// Оператор "FAIL" завершается ошибкой.
+type fakeDB struct {
+	mu    sync.Mutex
+	rows  map[int]appliedMigration
+	log   []string
+	locks int
+}
+
+type fakeDriver struct{ db *fakeDB }
+
+var fakeDriverSeq atomic.Int64
+
+func openFakeDB(t *testing.T) (*fakeDB, *sql.DB) {
+	t.Helper()
+	fake := &fakeDB{rows: map[int]appliedMigration{}}
+	name := fmt.Sprintf("migratortest-%d", fakeDriverSeq.Add(1))
+	sql.Register(name, fakeDriver{db: fake})
+	sqlDB, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return fake, sqlDB
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+func (f *fakeDB) executed(prefix string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, stmt := range f.log {
+		if strings.HasPrefix(stmt, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+func (f *fakeDB) hasVersion(version int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.rows[version]
+	return ok
+}
+
+func (f *fakeDB) versions() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	versions := make([]int, 0, len(f.rows))
+	for version := range f.rows {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+func (f *fakeDB) lockHeld() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.locks != 0
+}
+
+type fakeConn struct {
+	db       *fakeDB
+	snapshot map[int]appliedMigration
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = maps.Clone(c.db.rows)
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.rows = c.snapshot
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	query = strings.TrimSpace(query)
+	switch {
+	case query == "FAIL":
+		return nil, errors.New("fake driver: statement failed")
+	case strings.HasPrefix(query, "SELECT pg_advisory_lock"):
+		c.db.locks++
+	case strings.HasPrefix(query, "SELECT pg_advisory_unlock"):
+		c.db.locks--
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations"):
+		c.db.rows[int(args[0].Value.(int64))] = appliedMigration{
+			name:      args[1].Value.(string),
+			checksum:  args[2].Value.(string),
+			appliedAt: time.Now(),
+		}
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations"):
+		delete(c.db.rows, int(args[0].Value.(int64)))
+	default:
+		c.db.log = append(c.db.log, query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, "SELECT version, name, checksum, applied_at FROM schema_migrations") {
+		return nil, fmt.Errorf("fake driver: unexpected query %q", query)
+	}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	rows := &fakeRows{}
+	for version, row := range c.db.rows {
+		rows.values = append(rows.values, []driver.Value{int64(version), row.name, row.checksum, row.appliedAt})
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+	next   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"version", "name", "checksum", "applied_at"}
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+var ErrDraining = errors.New("shutting down")
+
+// Readiness отвечает на /readyz: база доступна, схема соответствует сборке и экземпляр
+// не останавливается. В отличие от /healthz, отказ здесь снимает экземпляр с балансировки,
+// но не перезапускает его.
+type Readiness struct {
+	db       *gorm.DB
+	migrator *Migrator
+	draining atomic.Bool
+}
+
+func NewReadiness(database *gorm.DB, migrator *Migrator) *Readiness {
+	return &Readiness{db: database, migrator: migrator}
+}
+
+func (r *Readiness) Ready(ctx context.Context) error {
+	if r.draining.Load() {
+		return ErrDraining
+	}
+	if err := HealthCheck(ctx, r.db); err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	if err := r.migrator.Check(ctx); err != nil {
+		return fmt.Errorf("migrations: %w", err)
+	}
+	return nil
+}
+
+// Drain переводит экземпляр в «не готов» перед остановкой, чтобы балансировщик
+// перестал присылать новые запросы, пока дорабатывают текущие.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+)
+
+// stubSchemaStatements — минимальная копия общей схемы Snowops: только таблицы и колонки,
+// которые читает сервис. В общей базе таблицы уже есть, и CREATE IF NOT EXISTS их не трогает.
+var stubSchemaStatements = []string{
+	`CREATE EXTENSION IF NOT EXISTS "uuid-ossp";`,
+	`CREATE TABLE IF NOT EXISTS organizations (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		parent_org_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+		type VARCHAR(32) NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS drivers (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+		full_name VARCHAR(255) NOT NULL,
+		phone VARCHAR(32),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS vehicles (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+		plate_number VARCHAR(32) NOT NULL,
+		brand VARCHAR(64),
+		model VARCHAR(64),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS users (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		login VARCHAR(255) NOT NULL UNIQUE,
+		role VARCHAR(32) NOT NULL,
+		organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL,
+		driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS cleaning_areas (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		name TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS polygons (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		name TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	// Область LANDFILL_* строится по владельцу полигона.
+	`ALTER TABLE polygons ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES organizations(id) ON DELETE SET NULL;`,
+	`CREATE TABLE IF NOT EXISTS tickets (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		contractor_id UUID NOT NULL REFERENCES organizations(id),
+		cleaning_area_id UUID NOT NULL REFERENCES cleaning_areas(id),
+		status VARCHAR(32) NOT NULL DEFAULT 'PLANNED',
+		planned_start_at TIMESTAMPTZ,
+		planned_end_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+	`CREATE TABLE IF NOT EXISTS trips (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		ticket_id UUID REFERENCES tickets(id) ON DELETE SET NULL,
+		driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+		vehicle_id UUID REFERENCES vehicles(id) ON DELETE SET NULL,
+		polygon_id UUID REFERENCES polygons(id) ON DELETE SET NULL,
+		status VARCHAR(32) NOT NULL DEFAULT 'OK',
+		entry_at TIMESTAMPTZ,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`,
+}
+
+// DemoUser — пользователь демо-данных; по нему seed выпускает токены для каждой роли.
+type DemoUser struct {
+	ID       uuid.UUID
+	Login    string
+	Role     model.UserRole
+	OrgID    uuid.UUID
+	DriverID *uuid.UUID
+}
+
+var (
+	demoAkimatOrg      = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	demoKguOrg         = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	demoContractorOrg  = uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	demoContractorOrgB = uuid.MustParse("00000000-0000-0000-0000-000000000004")
+	demoLandfillOrg    = uuid.MustParse("00000000-0000-0000-0000-000000000005")
+	demoDriver         = uuid.MustParse("00000000-0000-0000-0000-000000000031")
+)
+
+var DemoUsers = []DemoUser{
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000011"), Login: "akimat.admin", Role: model.UserRoleAkimatAdmin, OrgID: demoAkimatOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000012"), Login: "akimat.user", Role: model.UserRoleAkimatUser, OrgID: demoAkimatOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000013"), Login: "kgu.admin", Role: model.UserRoleKguZkhAdmin, OrgID: demoKguOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000014"), Login: "kgu.user", Role: model.UserRoleKguZkhUser, OrgID: demoKguOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000015"), Login: "contractor.admin", Role: model.UserRoleContractorAdmin, OrgID: demoContractorOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000016"), Login: "driver", Role: model.UserRoleDriver, OrgID: demoContractorOrg, DriverID: &demoDriver},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000017"), Login: "landfill.admin", Role: model.UserRoleLandfillAdmin, OrgID: demoLandfillOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000018"), Login: "landfill.user", Role: model.UserRoleLandfillUser, OrgID: demoLandfillOrg},
+	{ID: uuid.MustParse("00000000-0000-0000-0000-000000000019"), Login: "contractor.b.admin", Role: model.UserRoleContractorAdmin, OrgID: demoContractorOrgB},
+}
+
+// demoDataStatements заполняют справочники общей схемы. Все вставки идут с ON CONFLICT,
+// поэтому повторный seed ничего не дублирует.
+var demoDataStatements = []string{
+	`INSERT INTO organizations (id, parent_org_id, type, name) VALUES
+		('00000000-0000-0000-0000-000000000001', NULL, 'AKIMAT', 'Акимат города'),
+		('00000000-0000-0000-0000-000000000002', '00000000-0000-0000-0000-000000000001', 'KGU', 'КГУ «Управление ЖКХ»'),
+		('00000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000002', 'CONTRACTOR', 'ТОО «Снегоуборка Север»'),
+		('00000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000002', 'CONTRACTOR', 'ТОО «Чистый Город»'),
+		('00000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000001', 'LANDFILL', 'Полигон «Восточный»')
+	ON CONFLICT (id) DO NOTHING;`,
+	`INSERT INTO drivers (id, organization_id, full_name, phone) VALUES
+		('00000000-0000-0000-0000-000000000031', '00000000-0000-0000-0000-000000000003', 'Ерлан Сапаров', '+77010000031'),
+		('00000000-0000-0000-0000-000000000032', '00000000-0000-0000-0000-000000000004', 'Андрей Ким', '+77010000032')
+	ON CONFLICT (id) DO NOTHING;`,
+	`INSERT INTO vehicles (id, organization_id, plate_number, brand, model) VALUES
+		('00000000-0000-0000-0000-000000000041', '00000000-0000-0000-0000-000000000003', '123ABC01', 'КАМАЗ', '65115'),
+		('00000000-0000-0000-0000-000000000042', '00000000-0000-0000-0000-000000000004', '456DEF01', 'MAN', 'TGS 33.400')
+	ON CONFLICT (id) DO NOTHING;`,
+	`INSERT INTO cleaning_areas (id, name) VALUES
+		('00000000-0000-0000-0000-000000000051', 'Участок №1 — проспект Абая'),
+		('00000000-0000-0000-0000-000000000052', 'Участок №2 — улица Сейфуллина')
+	ON CONFLICT (id) DO NOTHING;`,
+	`INSERT INTO polygons (id, name, organization_id) VALUES
+		('00000000-0000-0000-0000-000000000061', 'Снежный полигон «Восточный»', '00000000-0000-0000-0000-000000000005')
+	ON CONFLICT (id) DO NOTHING;`,
+	`INSERT INTO tickets (id, contractor_id, cleaning_area_id, status, planned_start_at, planned_end_at) VALUES
+		('00000000-0000-0000-0000-000000000081', '00000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000051', 'IN_PROGRESS', NOW() - INTERVAL '1 day', NOW() + INTERVAL '1 day'),
+		('00000000-0000-0000-0000-000000000082', '00000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000052', 'IN_PROGRESS', NOW() - INTERVAL '1 day', NOW() + INTERVAL '1 day')
+	ON CONFLICT (id) DO NOTHING;`,
+	`INSERT INTO trips (id, ticket_id, driver_id, vehicle_id, polygon_id, status, entry_at) VALUES
+		('00000000-0000-0000-0000-000000000071', '00000000-0000-0000-0000-000000000081', '00000000-0000-0000-0000-000000000031', '00000000-0000-0000-0000-000000000041', '00000000-0000-0000-0000-000000000061', 'OK', NOW() - INTERVAL '6 hours'),
+		('00000000-0000-0000-0000-000000000072', '00000000-0000-0000-0000-000000000081', '00000000-0000-0000-0000-000000000031', '00000000-0000-0000-0000-000000000041', '00000000-0000-0000-0000-000000000061', 'OK', NOW() - INTERVAL '4 hours'),
+		('00000000-0000-0000-0000-000000000073', '00000000-0000-0000-0000-000000000082', '00000000-0000-0000-0000-000000000032', '00000000-0000-0000-0000-000000000042', '00000000-0000-0000-0000-000000000061', 'OK', NOW() - INTERVAL '3 hours'),
+		('00000000-0000-0000-0000-000000000074', '00000000-0000-0000-0000-000000000082', '00000000-0000-0000-0000-000000000032', '00000000-0000-0000-0000-000000000042', NULL, 'OK', NOW() - INTERVAL '2 hours')
+	ON CONFLICT (id) DO NOTHING;`,
+}
+
+// demoScenarioStatements переводят рейсы в нарушающие статусы — нарушения создают
+// триггеры сервиса, как в бою, — и подают от водителя апелляцию на одно из них.
+var demoScenarioStatements = []string{
+	`UPDATE trips SET status = 'ROUTE_VIOLATION' WHERE id = '00000000-0000-0000-0000-000000000072' AND status = 'OK';`,
+	`UPDATE trips SET status = 'OVER_CAPACITY' WHERE id = '00000000-0000-0000-0000-000000000073' AND status = 'OK';`,
+	`UPDATE trips SET status = 'MISMATCH_PLATE' WHERE id = '00000000-0000-0000-0000-000000000074' AND status = 'OK';`,
+	`WITH appeal AS (
+		INSERT INTO violation_appeals (violation_id, trip_id, ticket_id, driver_id, contractor_id, reason_code, reason_text, due_at)
+		SELECT v.id, v.trip_id, '00000000-0000-0000-0000-000000000081', '00000000-0000-0000-0000-000000000031',
+			'00000000-0000-0000-0000-000000000003', 'TRANSIT_PATH', 'Объезд перекрытого участка по указанию диспетчера', NOW() + INTERVAL '48 hours'
+		FROM violations v
+		WHERE v.trip_id = '00000000-0000-0000-0000-000000000072'
+			AND NOT EXISTS (SELECT 1 FROM violation_appeals a WHERE a.violation_id = v.id)
+		LIMIT 1
+		RETURNING id
+	)
+	INSERT INTO appeal_status_log (appeal_id, old_status, new_status, note, changed_by)
+	SELECT id, NULL, 'SUBMITTED', 'demo seed', '00000000-0000-0000-0000-000000000016' FROM appeal;`,
+}
+
+// Seed создаёт заглушку общей схемы, применяет миграции и загружает демо-данные
+// для всех ролей. Предназначен для локальной разработки.
+func Seed(ctx context.Context, database *gorm.DB, log zerolog.Logger) error {
+	for i, stmt := range stubSchemaStatements {
+		if err := database.WithContext(ctx).Exec(stmt).Error; err != nil {
+			return fmt.Errorf("stub schema statement %d: %w", i+1, err)
+		}
+	}
+
+	migrator, err := NewMigrator(database, log)
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+
+	return database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, stmt := range demoDataStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("demo data statement %d: %w", i+1, err)
+			}
+		}
+		for _, user := range DemoUsers {
+			if err := tx.Exec(
+				`INSERT INTO users (id, login, role, organization_id, driver_id) VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (id) DO NOTHING`,
+				user.ID, user.Login, string(user.Role), user.OrgID, user.DriverID,
+			).Error; err != nil {
+				return fmt.Errorf("demo user %s: %w", user.Login, err)
+			}
+		}
+		for i, stmt := range demoScenarioStatements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("demo scenario statement %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
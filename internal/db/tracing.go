@@ -0,0 +1,85 @@
+package db
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanInstanceKey = "violation-service:span"
+
+var tracer = otel.Tracer("violation-service/internal/db")
+
+// registerTracing оборачивает каждую операцию GORM в спан. В атрибуты попадает SQL
+// с плейсхолдерами, без значений параметров: в них бывают персональные данные.
+// Каждый Preload выполняется отдельным запросом и получает собственный спан.
+func registerTracing(database *gorm.DB) error {
+	type stage struct {
+		op            string
+		before, after func(name string, fn func(*gorm.DB)) error
+	}
+	callbacks := database.Callback()
+	stages := []stage{
+		{"INSERT", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"SELECT", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"UPDATE", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"DELETE", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"ROW", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"RAW", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+	for _, st := range stages {
+		op := st.op
+		if err := st.before("tracing:before_"+op, func(tx *gorm.DB) { startQuerySpan(tx, op) }); err != nil {
+			return err
+		}
+		if err := st.after("tracing:after_"+op, endQuerySpan); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuerySpan(tx *gorm.DB, op string) {
+	if tx.Statement == nil || tx.Statement.Context == nil {
+		return
+	}
+	name := "db " + op
+	if tx.Statement.Table != "" {
+		name += " " + tx.Statement.Table
+	}
+	ctx, span := tracer.Start(tx.Statement.Context, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", op),
+			attribute.String("db.sql.table", tx.Statement.Table),
+		),
+	)
+	tx.Statement.Context = ctx
+	tx.InstanceSet(spanInstanceKey, span)
+}
+
+func endQuerySpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.statement", tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if err := tx.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// utf8BOM нужен, чтобы Excel корректно открывал кириллицу в CSV.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(out io.Writer) (*csvWriter, error) {
+	if _, err := out.Write(utf8BOM); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(out)}, nil
+}
+
+func (c *csvWriter) WriteRow(cells []string) error {
+	sanitized := make([]string, len(cells))
+	for i, cell := range cells {
+		sanitized[i] = sanitizeCell(cell)
+	}
+	return c.w.Write(sanitized)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"strconv"
+	"time"
+
+	"violation-service/internal/model"
+)
+
+var ViolationColumns = []string{
+	"violation_id",
+	"created_at",
+	"status",
+	"type",
+	"severity",
+	"detected_by",
+	"description",
+	"trip_id",
+	"trip_status",
+	"trip_entry_at",
+	"trip_violation_reason",
+	"contractor_id",
+	"contractor_name",
+	"ticket_id",
+	"ticket_status",
+	"driver_id",
+	"driver_name",
+	"driver_phone",
+	"vehicle_plate",
+	"cleaning_area",
+	"polygon",
+	"last_appeal_status",
+	"last_appeal_reason_code",
+	"has_active_appeal",
+}
+
+// ViolationRow разворачивает ViolationRecord в плоскую строку в порядке ViolationColumns.
+func ViolationRow(record model.ViolationRecord) []string {
+	var contractorID, contractorName, ticketID, ticketStatus string
+	var driverID, driverName, driverPhone, plate, area string
+	var appealStatus, appealReason string
+
+	if record.Contractor != nil {
+		contractorID = record.Contractor.ID.String()
+		contractorName = record.Contractor.Name
+	}
+	if record.Ticket != nil {
+		ticketID = record.Ticket.ID.String()
+		ticketStatus = record.Ticket.Status
+	}
+	if record.Driver != nil {
+		driverID = record.Driver.ID.String()
+		driverName = record.Driver.FullName
+		driverPhone = record.Driver.Phone
+	}
+	if record.Vehicle != nil {
+		plate = record.Vehicle.PlateNumber
+	}
+	if record.Area != nil {
+		area = record.Area.Name
+	}
+	if record.LastAppeal != nil {
+		appealStatus = string(record.LastAppeal.Status)
+		appealReason = string(record.LastAppeal.ReasonCode)
+	}
+
+	v := record.Violation
+	return []string{
+		v.ID.String(),
+		formatTime(&v.CreatedAt),
+		string(v.Status),
+		string(v.Type),
+		string(v.Severity),
+		string(v.DetectedBy),
+		v.Description,
+		v.TripID.String(),
+		record.TripStatus,
+		formatTime(record.TripEntryAt),
+		deref(record.TripViolationReason),
+		contractorID,
+		contractorName,
+		ticketID,
+		ticketStatus,
+		driverID,
+		driverName,
+		driverPhone,
+		plate,
+		area,
+		deref(record.PolygonName),
+		appealStatus,
+		appealReason,
+		strconv.FormatBool(record.HasActiveAppeal),
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
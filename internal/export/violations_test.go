@@ -0,0 +1,101 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"violation-service/internal/model"
+)
+
+func cell(t *testing.T, row []string, column string) string {
+	t.Helper()
+	for i, name := range ViolationColumns {
+		if name == column {
+			return row[i]
+		}
+	}
+	t.Fatalf("unknown column %q", column)
+	return ""
+}
+
+func TestViolationRowFull(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 8, 4, 5, 0, time.FixedZone("ALMT", 5*3600))
+	entryAt := createdAt.Add(-time.Hour)
+	reason := "Auto violation: ROUTE_VIOLATION"
+	polygon := "Полигон №1"
+	record := model.ViolationRecord{
+		Violation: model.Violation{
+			ID:          uuid.New(),
+			TripID:      uuid.New(),
+			Type:        model.ViolationTypeRouteViolation,
+			DetectedBy:  model.ViolationDetectedByGps,
+			Severity:    model.ViolationSeverityHigh,
+			Status:      model.ViolationStatusOpen,
+			Description: "съезд с маршрута",
+			CreatedAt:   createdAt,
+		},
+		TripStatus:          "ROUTE_VIOLATION",
+		TripEntryAt:         &entryAt,
+		TripViolationReason: &reason,
+		Contractor:          &model.OrgBrief{ID: uuid.New(), Name: "ТОО Снег"},
+		Ticket:              &model.TicketBrief{ID: uuid.New(), Status: "IN_PROGRESS"},
+		Driver:              &model.DriverBrief{ID: uuid.New(), FullName: "Иванов И.", Phone: "+77010000000"},
+		Vehicle:             &model.VehicleBrief{ID: uuid.New(), PlateNumber: "123ABC02"},
+		Area:                &model.AreaBrief{ID: uuid.New(), Name: "Алмалинский"},
+		PolygonName:         &polygon,
+		LastAppeal:          &model.AppealBrief{Status: model.AppealStatusNeedInfo, ReasonCode: model.AppealReasonCameraError},
+		HasActiveAppeal:     true,
+	}
+
+	row := ViolationRow(record)
+	if len(row) != len(ViolationColumns) {
+		t.Fatalf("row has %d cells, want %d", len(row), len(ViolationColumns))
+	}
+	want := map[string]string{
+		"violation_id":            record.Violation.ID.String(),
+		"created_at":              "2025-01-02T03:04:05Z",
+		"trip_entry_at":           "2025-01-02T02:04:05Z",
+		"trip_violation_reason":   reason,
+		"contractor_name":         "ТОО Снег",
+		"ticket_status":           "IN_PROGRESS",
+		"driver_name":             "Иванов И.",
+		"driver_phone":            "+77010000000",
+		"vehicle_plate":           "123ABC02",
+		"cleaning_area":           "Алмалинский",
+		"polygon":                 polygon,
+		"last_appeal_status":      "NEED_INFO",
+		"last_appeal_reason_code": "CAMERA_ERROR",
+		"has_active_appeal":       "true",
+	}
+	for column, value := range want {
+		if got := cell(t, row, column); got != value {
+			t.Errorf("%s = %q, want %q", column, got, value)
+		}
+	}
+}
+
+func TestViolationRowWithoutRelations(t *testing.T) {
+	record := model.ViolationRecord{
+		Violation: model.Violation{ID: uuid.New(), TripID: uuid.New(), Status: model.ViolationStatusOpen},
+	}
+
+	row := ViolationRow(record)
+	if len(row) != len(ViolationColumns) {
+		t.Fatalf("row has %d cells, want %d", len(row), len(ViolationColumns))
+	}
+	for _, column := range []string{
+		"created_at", "trip_status", "trip_entry_at", "trip_violation_reason",
+		"contractor_id", "contractor_name", "ticket_id", "ticket_status",
+		"driver_id", "driver_name", "driver_phone", "vehicle_plate",
+		"cleaning_area", "polygon", "last_appeal_status", "last_appeal_reason_code",
+	} {
+		if got := cell(t, row, column); got != "" {
+			t.Errorf("%s = %q, want empty", column, got)
+		}
+	}
+	if got := cell(t, row, "has_active_appeal"); got != "false" {
+		t.Errorf("has_active_appeal = %q, want false", got)
+	}
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// RowWriter пишет таблицу построчно, не накапливая строки в памяти.
+type RowWriter interface {
+	WriteRow(cells []string) error
+	Flush() error
+	Close() error
+}
+
+// formulaPrefixes — первые символы, с которых Excel и LibreOffice начинают формулу.
+const formulaPrefixes = "=+-@\t\r"
+
+// sanitizeCell экранирует значение, которое табличный редактор принял бы за формулу:
+// описание, причина рейса, ФИО и номер приходят от пользователей, а файлы открывают
+// сотрудники акимата и КГУ. Апостроф в начале Excel показывает как текст.
+func sanitizeCell(cell string) string {
+	if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func NewRowWriter(format string, out io.Writer, sheetName string) (RowWriter, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return newCSVWriter(out)
+	case FormatXLSX:
+		return newXLSXWriter(out, sheetName)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func ContentType(format string) string {
+	if strings.ToLower(format) == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func IsSupported(format string) bool {
+	format = strings.ToLower(format)
+	return format == FormatCSV || format == FormatXLSX
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeCell(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "обычный текст", want: "обычный текст"},
+		{in: "123ABC02", want: "123ABC02"},
+		{in: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{in: "+7 701 000 00 00", want: "'+7 701 000 00 00"},
+		{in: "-2+3", want: "'-2+3"},
+		{in: "@SUM(A1)", want: "'@SUM(A1)"},
+		{in: "\t=1", want: "'\t=1"},
+		{in: "\r=1", want: "'\r=1"},
+		{in: "a=1", want: "a=1"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeCell(tt.in); got != tt.want {
+			t.Errorf("sanitizeCell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCSVWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewRowWriter(FormatCSV, &buf, "violations")
+	if err != nil {
+		t.Fatalf("NewRowWriter: %v", err)
+	}
+	if err := w.WriteRow([]string{"=cmd|' /C calc'!A0", "ok"}); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	body := bytes.TrimPrefix(buf.Bytes(), utf8BOM)
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if got := records[0][0]; got != "'=cmd|' /C calc'!A0" {
+		t.Errorf("formula cell = %q, want it prefixed with '", got)
+	}
+	if got := records[0][1]; got != "ok" {
+		t.Errorf("plain cell = %q, want ok", got)
+	}
+}
+
+func TestXLSXWriterEscapesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewRowWriter(FormatXLSX, &buf, "violations")
+	if err != nil {
+		t.Fatalf("NewRowWriter: %v", err)
+	}
+	if err := w.WriteRow([]string{"@SUM(A1)", "ok"}); err != nil {
+		t.Fatalf("WriteRow: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	sheet := readZipPart(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	if !strings.Contains(sheet, `<c r="A1" t="inlineStr"><is><t xml:space="preserve">&#39;@SUM(A1)</t></is></c>`) {
+		t.Errorf("sheet does not contain the escaped formula cell:\n%s", sheet)
+	}
+	if !strings.Contains(sheet, `<c r="B1" t="inlineStr"><is><t xml:space="preserve">ok</t></is></c>`) {
+		t.Errorf("sheet does not contain the plain cell:\n%s", sheet)
+	}
+}
+
+func readZipPart(t *testing.T, archive []byte, name string) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", name, err)
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(body)
+	}
+	t.Fatalf("xlsx has no %s", name)
+	return ""
+}
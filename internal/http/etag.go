@@ -0,0 +1,43 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"violation-service/internal/service"
+)
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+var (
+	errIfMatchMissing = service.ErrPreconditionRequired.WithMessage(ifMatchHeader + " header is required")
+	errIfMatchInvalid = service.InvalidField(ifMatchHeader, "must be an ETag returned by the API")
+)
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+func setETag(c *gin.Context, version int64) {
+	c.Header(etagHeader, formatETag(version))
+}
+
+// parseIfMatch извлекает версию из If-Match; допускается и слабый вариант W/"N".
+func parseIfMatch(c *gin.Context) (int64, error) {
+	raw := strings.TrimSpace(c.GetHeader(ifMatchHeader))
+	if raw == "" {
+		return 0, errIfMatchMissing
+	}
+	raw = strings.TrimPrefix(raw, "W/")
+	raw = strings.Trim(raw, `"`)
+	version, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errIfMatchInvalid
+	}
+	return version, nil
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"violation-service/internal/service"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int64
+		wantErr     *service.Error
+		wantStatus  int
+		wantCode    string
+	}{
+		{name: "missing", wantErr: service.ErrPreconditionRequired, wantStatus: http.StatusPreconditionRequired, wantCode: "precondition_required"},
+		{name: "not a version", header: `"abc"`, wantErr: service.ErrInvalidInput, wantStatus: http.StatusBadRequest, wantCode: "invalid_input"},
+		{name: "zero", header: `"0"`, wantErr: service.ErrInvalidInput, wantStatus: http.StatusBadRequest, wantCode: "invalid_input"},
+		{name: "strong", header: `"7"`, wantVersion: 7},
+		{name: "weak", header: ` W/"3" `, wantVersion: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+			if tt.header != "" {
+				c.Request.Header.Set(ifMatchHeader, tt.header)
+			}
+
+			version, err := parseIfMatch(c)
+			if tt.wantErr == nil {
+				if err != nil || version != tt.wantVersion {
+					t.Fatalf("parseIfMatch = %d, %v; want %d, nil", version, err, tt.wantVersion)
+				}
+				return
+			}
+			var apiErr *service.Error
+			if !errors.As(err, &apiErr) || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseIfMatch error = %v, want %v", err, tt.wantErr)
+			}
+			if apiErr.Status != tt.wantStatus || apiErr.Code != tt.wantCode {
+				t.Errorf("error = %d %s, want %d %s", apiErr.Status, apiErr.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
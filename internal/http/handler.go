@@ -1,6 +1,8 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,64 +10,189 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 
+	"violation-service/internal/export"
 	"violation-service/internal/http/middleware"
+	"violation-service/internal/logger"
 	"violation-service/internal/model"
 	"violation-service/internal/service"
 )
 
+var (
+	// errPrincipalMissing означает, что маршрут зарегистрирован без middleware.Auth.
+	errPrincipalMissing = service.ErrUnauthenticated.WithMessage("principal missing")
+	errInvalidID        = invalidUUID("id")
+)
+
 type Handler struct {
-	violationService *service.ViolationService
-	appealService    *service.AppealService
-	log              zerolog.Logger
+	violationService      *service.ViolationService
+	appealService         *service.AppealService
+	webhookService        *service.WebhookService
+	needInfoPolicyService *service.NeedInfoPolicyService
+	sessionService        *service.SessionService
+	violationTypeService  *service.ViolationTypeService
+	tripStatusRuleService *service.TripStatusRuleService
 }
 
 func NewHandler(
 	violationService *service.ViolationService,
 	appealService *service.AppealService,
-	log zerolog.Logger,
+	webhookService *service.WebhookService,
+	needInfoPolicyService *service.NeedInfoPolicyService,
+	sessionService *service.SessionService,
+	violationTypeService *service.ViolationTypeService,
+	tripStatusRuleService *service.TripStatusRuleService,
 ) *Handler {
 	return &Handler{
-		violationService: violationService,
-		appealService:    appealService,
-		log:              log,
+		violationService:      violationService,
+		appealService:         appealService,
+		webhookService:        webhookService,
+		needInfoPolicyService: needInfoPolicyService,
+		sessionService:        sessionService,
+		violationTypeService:  violationTypeService,
+		tripStatusRuleService: tripStatusRuleService,
 	}
 }
 
 func (h *Handler) listViolations(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	opts, err := parseViolationQuery(c)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	page, err := h.violationService.List(c.Request.Context(), principal, opts)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Формат как в аналитике (reports): data — объект с полем списка (events → items) и курсором
+	c.JSON(http.StatusOK, successResponse(page))
+}
+
+func (h *Handler) violationStats(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	opts, err := parseViolationQuery(c)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var groupBy []model.StatsDimension
+	for _, val := range splitCSV(c.Query("group_by")) {
+		groupBy = append(groupBy, model.StatsDimension(strings.ToLower(val)))
+	}
+
+	stats, err := h.violationService.Stats(c.Request.Context(), principal, opts, groupBy)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(stats))
+}
+
+func (h *Handler) exportViolations(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", export.FormatCSV)))
+	if !export.IsSupported(format) {
+		h.handleError(c, service.InvalidField("format", "unknown format", export.FormatCSV, export.FormatXLSX))
 		return
 	}
 
 	opts, err := parseViolationQuery(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, err)
 		return
 	}
 
-	records, err := h.violationService.List(c.Request.Context(), principal, opts)
+	violationExport, err := h.violationService.PrepareExport(c.Request.Context(), principal, opts)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	// Формат как в аналитике (reports): data — объект с полем списка (events → items)
-	c.JSON(http.StatusOK, successResponse(gin.H{"items": records}))
+	// HTTP_WRITE_TIMEOUT рассчитан на обычные ответы, а экспорт не ограничен по объёму:
+	// вместо общего дедлайна каждой пачке даётся своё окно на запись.
+	extendExportDeadline(c)
+
+	filename := fmt.Sprintf("violations-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	writer, err := export.NewRowWriter(format, c.Writer, "violations")
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error().Err(err).Msg("export init failed")
+		return
+	}
+	if err := writer.WriteRow(export.ViolationColumns); err != nil {
+		logger.FromContext(c.Request.Context()).Error().Err(err).Msg("export write failed")
+		return
+	}
+
+	// Заголовки уже отправлены: при ошибке остаётся только оборвать поток и залогировать.
+	err = violationExport.Each(c.Request.Context(), func(records []model.ViolationRecord) error {
+		for _, record := range records {
+			if err := writer.WriteRow(export.ViolationRow(record)); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		extendExportDeadline(c)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error().Err(err).Msg("export aborted")
+		return
+	}
+	if err := writer.Close(); err != nil {
+		logger.FromContext(c.Request.Context()).Error().Err(err).Msg("export finalize failed")
+	}
+}
+
+// exportWriteWindow — сколько клиенту даётся на приём одной пачки экспорта; зависший
+// клиент обрывается, а медленная, но живая выгрузка идёт сколько угодно долго.
+const exportWriteWindow = time.Minute
+
+func extendExportDeadline(c *gin.Context) {
+	err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(exportWriteWindow))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		logger.FromContext(c.Request.Context()).Warn().Err(err).Msg("export: failed to extend write deadline")
+	}
 }
 
 func (h *Handler) getViolation(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid violation id"))
+		h.handleError(c, errInvalidID)
 		return
 	}
 
@@ -75,13 +202,58 @@ func (h *Handler) getViolation(c *gin.Context) {
 		return
 	}
 
+	setETag(c, details.Record.Violation.Version)
 	c.JSON(http.StatusOK, successResponse(details))
 }
 
+func (h *Handler) getViolationHistory(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	history, err := h.violationService.History(c.Request.Context(), principal, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"items": history}))
+}
+
+func (h *Handler) getViolationTimeline(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	events, err := h.violationService.Timeline(c.Request.Context(), principal, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"items": events}))
+}
+
 func (h *Handler) createViolation(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
@@ -89,17 +261,17 @@ func (h *Handler) createViolation(c *gin.Context) {
 		TripID      string `json:"trip_id" binding:"required"`
 		Type        string `json:"type" binding:"required"`
 		DetectedBy  string `json:"detected_by" binding:"required"`
-		Severity    string `json:"severity" binding:"required"`
+		Severity    string `json:"severity"`
 		Description string `json:"description"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, invalidBody(err))
 		return
 	}
 
 	tripID, err := uuid.Parse(strings.TrimSpace(req.TripID))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid trip_id"))
+		h.handleError(c, invalidUUID("trip_id"))
 		return
 	}
 
@@ -127,13 +299,19 @@ func (h *Handler) createViolation(c *gin.Context) {
 func (h *Handler) updateViolationStatus(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid violation id"))
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		h.handleError(c, err)
 		return
 	}
 
@@ -142,13 +320,23 @@ func (h *Handler) updateViolationStatus(c *gin.Context) {
 		Description string `json:"description"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, invalidBody(err))
 		return
 	}
 
 	status := model.ViolationStatus(strings.ToUpper(strings.TrimSpace(req.Status)))
 
-	if err := h.violationService.UpdateStatus(c.Request.Context(), principal, id, status, req.Description); err != nil {
+	if err := h.violationService.UpdateStatus(c.Request.Context(), principal, id, version, status, req.Description); err != nil {
+		if errors.Is(err, service.ErrPrecondition) {
+			current, getErr := h.violationService.GetDetails(c.Request.Context(), principal, id)
+			if getErr != nil {
+				h.handleError(c, getErr)
+				return
+			}
+			setETag(c, current.Record.Violation.Version)
+			c.JSON(http.StatusPreconditionFailed, preconditionResponse(current))
+			return
+		}
 		h.handleError(c, err)
 		return
 	}
@@ -156,39 +344,87 @@ func (h *Handler) updateViolationStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, successResponse(gin.H{"status": "updated"}))
 }
 
+func (h *Handler) reopenViolation(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+		Appeal string `json:"appeal"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	mode := service.ReopenAppealMode(strings.ToUpper(strings.TrimSpace(req.Appeal)))
+
+	if err := h.appealService.ReopenViolation(c.Request.Context(), principal, id, version, req.Reason, mode); err != nil {
+		if errors.Is(err, service.ErrPrecondition) {
+			current, getErr := h.violationService.GetDetails(c.Request.Context(), principal, id)
+			if getErr != nil {
+				h.handleError(c, getErr)
+				return
+			}
+			setETag(c, current.Record.Violation.Version)
+			c.JSON(http.StatusPreconditionFailed, preconditionResponse(current))
+			return
+		}
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"status": "reopened"}))
+}
+
 func (h *Handler) listAppeals(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	opts, err := parseAppealQuery(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, err)
 		return
 	}
 
-	appeals, err := h.appealService.List(c.Request.Context(), principal, opts)
+	page, err := h.appealService.List(c.Request.Context(), principal, opts)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	// Формат как в аналитике: data — объект с полем items
-	c.JSON(http.StatusOK, successResponse(gin.H{"items": appeals}))
+	// Формат как в аналитике: data — объект с полем items и курсором
+	c.JSON(http.StatusOK, successResponse(page))
 }
 
 func (h *Handler) getAppeal(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid appeal id"))
+		h.handleError(c, errInvalidID)
 		return
 	}
 
@@ -198,19 +434,42 @@ func (h *Handler) getAppeal(c *gin.Context) {
 		return
 	}
 
+	setETag(c, appeal.Version)
 	c.JSON(http.StatusOK, successResponse(appeal))
 }
 
+func (h *Handler) getAppealHistory(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	history, err := h.appealService.History(c.Request.Context(), principal, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"items": history}))
+}
+
 func (h *Handler) createAppeal(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	violationID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid violation id"))
+		h.handleError(c, errInvalidID)
 		return
 	}
 
@@ -220,7 +479,7 @@ func (h *Handler) createAppeal(c *gin.Context) {
 		Attachments []AttachmentPayload `json:"attachments"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, invalidBody(err))
 		return
 	}
 
@@ -239,13 +498,13 @@ func (h *Handler) createAppeal(c *gin.Context) {
 func (h *Handler) addAppealComment(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	appealID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid appeal id"))
+		h.handleError(c, errInvalidID)
 		return
 	}
 
@@ -254,7 +513,7 @@ func (h *Handler) addAppealComment(c *gin.Context) {
 		Attachments []AttachmentPayload `json:"attachments"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, invalidBody(err))
 		return
 	}
 
@@ -271,13 +530,19 @@ func (h *Handler) addAppealComment(c *gin.Context) {
 func (h *Handler) actOnAppeal(c *gin.Context) {
 	principal, ok := middleware.MustPrincipal(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, errorResponse("principal missing"))
+		h.handleError(c, errPrincipalMissing)
 		return
 	}
 
 	appealID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse("invalid appeal id"))
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		h.handleError(c, err)
 		return
 	}
 
@@ -286,13 +551,23 @@ func (h *Handler) actOnAppeal(c *gin.Context) {
 		Message string `json:"message"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
+		h.handleError(c, invalidBody(err))
 		return
 	}
 
 	action := service.AppealAction(strings.ToUpper(strings.TrimSpace(req.Action)))
 
-	if err := h.appealService.Act(c.Request.Context(), principal, appealID, action, req.Message); err != nil {
+	if err := h.appealService.Act(c.Request.Context(), principal, appealID, version, action, req.Message); err != nil {
+		if errors.Is(err, service.ErrPrecondition) {
+			current, getErr := h.appealService.Get(c.Request.Context(), principal, appealID)
+			if getErr != nil {
+				h.handleError(c, getErr)
+				return
+			}
+			setETag(c, current.Version)
+			c.JSON(http.StatusPreconditionFailed, preconditionResponse(current))
+			return
+		}
 		h.handleError(c, err)
 		return
 	}
@@ -300,22 +575,16 @@ func (h *Handler) actOnAppeal(c *gin.Context) {
 	c.JSON(http.StatusOK, successResponse(gin.H{"status": "updated"}))
 }
 
+// handleError отдаёт ошибку сервиса с её кодом и статусом. Всё, что не *service.Error,
+// считается внутренней ошибкой: клиенту уходит только код internal, детали — в лог и спан.
 func (h *Handler) handleError(c *gin.Context, err error) {
-	switch err {
-	case service.ErrPermissionDenied:
-		c.JSON(http.StatusForbidden, errorResponse(err.Error()))
-	case service.ErrInvalidInput:
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
-	case service.ErrNotFound:
-		c.JSON(http.StatusNotFound, errorResponse(err.Error()))
-	case service.ErrConflict:
-		c.JSON(http.StatusConflict, errorResponse(err.Error()))
-	case service.ErrInvalidStatus:
-		c.JSON(http.StatusBadRequest, errorResponse(err.Error()))
-	default:
-		h.log.Error().Err(err).Msg("handler error")
-		c.JSON(http.StatusInternalServerError, errorResponse("internal error"))
+	var serviceErr *service.Error
+	if !errors.As(err, &serviceErr) {
+		logger.FromContext(c.Request.Context()).Error().Err(err).Msg("handler error")
+		trace.SpanFromContext(c.Request.Context()).RecordError(err)
+		serviceErr = service.ErrInternal
 	}
+	c.JSON(serviceErr.Status, errorResponse(serviceErr))
 }
 
 func parseViolationQuery(c *gin.Context) (service.ListViolationsOptions, error) {
@@ -345,7 +614,7 @@ func parseViolationQuery(c *gin.Context) (service.ListViolationsOptions, error)
 		for _, val := range splitCSV(contractorParam) {
 			id, err := uuid.Parse(val)
 			if err != nil {
-				return opts, err
+				return opts, invalidUUID("contractor_id")
 			}
 			opts.ContractorIDs = append(opts.ContractorIDs, id)
 		}
@@ -353,35 +622,35 @@ func parseViolationQuery(c *gin.Context) (service.ListViolationsOptions, error)
 	if driverID := strings.TrimSpace(c.Query("driver_id")); driverID != "" {
 		id, err := uuid.Parse(driverID)
 		if err != nil {
-			return opts, err
+			return opts, invalidUUID("driver_id")
 		}
 		opts.DriverID = &id
 	}
 	if ticketID := strings.TrimSpace(c.Query("ticket_id")); ticketID != "" {
 		id, err := uuid.Parse(ticketID)
 		if err != nil {
-			return opts, err
+			return opts, invalidUUID("ticket_id")
 		}
 		opts.TicketID = &id
 	}
 	if areaID := strings.TrimSpace(c.Query("cleaning_area_id")); areaID != "" {
 		id, err := uuid.Parse(areaID)
 		if err != nil {
-			return opts, err
+			return opts, invalidUUID("cleaning_area_id")
 		}
 		opts.CleaningAreaID = &id
 	}
 	if dateFrom := strings.TrimSpace(c.Query("date_from")); dateFrom != "" {
 		ts, err := time.Parse(time.RFC3339, dateFrom)
 		if err != nil {
-			return opts, err
+			return opts, service.InvalidField("date_from", "must be an RFC 3339 timestamp")
 		}
 		opts.DateFrom = &ts
 	}
 	if dateTo := strings.TrimSpace(c.Query("date_to")); dateTo != "" {
 		ts, err := time.Parse(time.RFC3339, dateTo)
 		if err != nil {
-			return opts, err
+			return opts, service.InvalidField("date_to", "must be an RFC 3339 timestamp")
 		}
 		opts.DateTo = &ts
 	}
@@ -395,6 +664,8 @@ func parseViolationQuery(c *gin.Context) (service.ListViolationsOptions, error)
 			opts.Offset = v
 		}
 	}
+	opts.Cursor = strings.TrimSpace(c.Query("cursor"))
+	opts.WithTotal = parseBoolQuery(c.Query("include_total"))
 
 	opts.Search = strings.TrimSpace(c.Query("search"))
 
@@ -404,6 +675,14 @@ func parseViolationQuery(c *gin.Context) (service.ListViolationsOptions, error)
 func parseAppealQuery(c *gin.Context) (service.AppealListOptions, error) {
 	var opts service.AppealListOptions
 
+	if overdue := strings.TrimSpace(c.Query("overdue")); overdue != "" {
+		v, err := strconv.ParseBool(overdue)
+		if err != nil {
+			return opts, service.InvalidField("overdue", "must be true or false")
+		}
+		opts.Overdue = &v
+	}
+
 	if statusParam := c.Query("status"); statusParam != "" {
 		for _, val := range splitCSV(statusParam) {
 			opts.Statuses = append(opts.Statuses, model.AppealStatus(strings.ToUpper(val)))
@@ -423,7 +702,7 @@ func parseAppealQuery(c *gin.Context) (service.AppealListOptions, error) {
 		for _, val := range splitCSV(contractorParam) {
 			id, err := uuid.Parse(val)
 			if err != nil {
-				return opts, err
+				return opts, invalidUUID("contractor_id")
 			}
 			opts.ContractorIDs = append(opts.ContractorIDs, id)
 		}
@@ -431,14 +710,14 @@ func parseAppealQuery(c *gin.Context) (service.AppealListOptions, error) {
 	if dateFrom := strings.TrimSpace(c.Query("date_from")); dateFrom != "" {
 		ts, err := time.Parse(time.RFC3339, dateFrom)
 		if err != nil {
-			return opts, err
+			return opts, service.InvalidField("date_from", "must be an RFC 3339 timestamp")
 		}
 		opts.DateFrom = &ts
 	}
 	if dateTo := strings.TrimSpace(c.Query("date_to")); dateTo != "" {
 		ts, err := time.Parse(time.RFC3339, dateTo)
 		if err != nil {
-			return opts, err
+			return opts, service.InvalidField("date_to", "must be an RFC 3339 timestamp")
 		}
 		opts.DateTo = &ts
 	}
@@ -452,6 +731,8 @@ func parseAppealQuery(c *gin.Context) (service.AppealListOptions, error) {
 			opts.Offset = v
 		}
 	}
+	opts.Cursor = strings.TrimSpace(c.Query("cursor"))
+	opts.WithTotal = parseBoolQuery(c.Query("include_total"))
 	return opts, nil
 }
 
@@ -471,6 +752,19 @@ func convertAttachmentPayloads(payloads []AttachmentPayload) []service.Attachmen
 	return result
 }
 
+func parseIntQuery(value string) int {
+	v, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func parseBoolQuery(value string) bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err == nil && v
+}
+
 func splitCSV(value string) []string {
 	parts := strings.Split(value, ",")
 	out := make([]string, 0, len(parts))
@@ -492,6 +786,21 @@ func successResponse(data interface{}) responseEnvelope {
 	return responseEnvelope{Data: data}
 }
 
-func errorResponse(msg string) gin.H {
-	return gin.H{"error": msg}
+func errorResponse(err *service.Error) gin.H {
+	return gin.H{"error": err}
+}
+
+func preconditionResponse(current interface{}) gin.H {
+	return gin.H{"error": service.ErrPrecondition, "current": current}
+}
+
+// invalidUUID — ошибка для параметра пути или запроса, который не разобрался как UUID.
+func invalidUUID(field string) *service.Error {
+	return service.InvalidField(field, "must be a UUID")
+}
+
+// invalidBody оборачивает ошибку ShouldBindJSON. Схему тела уже проверил ValidateRequest,
+// поэтому сюда доходят редкие случаи, и поля в них не разбираются.
+func invalidBody(err error) *service.Error {
+	return service.ErrInvalidInput.WithMessage("invalid request body: " + err.Error())
 }
@@ -1,13 +1,17 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
 
 	"violation-service/internal/auth"
 	"violation-service/internal/model"
+	"violation-service/internal/service"
+	"violation-service/internal/tracing"
 )
 
 const (
@@ -16,21 +20,38 @@ const (
 	principalContextKey = "principal"
 )
 
-func Auth(parser *auth.Parser) gin.HandlerFunc {
+// Без проверки отзыва запрос не пропускается: это не ошибка клиента, и повтор может помочь.
+var errSessionCheckUnavailable = &service.Error{Code: "unavailable", Message: "session check unavailable", Status: http.StatusServiceUnavailable}
+
+// SessionChecker сообщает, отозвана ли сессия токена (см. auth.RevocationCache).
+type SessionChecker interface {
+	IsRevoked(ctx context.Context, claims *auth.Claims) (bool, error)
+}
+
+func Auth(parser *auth.Parser, sessions SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		raw := c.GetHeader(authorizationHeader)
 		if raw == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "authorization header missing"})
+			abortWithError(c, service.ErrUnauthenticated.WithMessage("authorization header missing"))
 			return
 		}
 		parts := strings.SplitN(raw, " ", 2)
 		if len(parts) != 2 || !strings.EqualFold(parts[0], bearerPrefix) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header"})
+			abortWithError(c, service.ErrUnauthenticated.WithMessage("invalid authorization header"))
+			return
+		}
+		claims, err := parser.Parse(c.Request.Context(), parts[1])
+		if err != nil {
+			abortWithError(c, service.ErrUnauthenticated.WithMessage("invalid token"))
 			return
 		}
-		claims, err := parser.Parse(parts[1])
+		revoked, err := sessions.IsRevoked(c.Request.Context(), claims)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			abortWithError(c, errSessionCheckUnavailable)
+			return
+		}
+		if revoked {
+			abortWithError(c, service.ErrUnauthenticated.WithMessage("session revoked"))
 			return
 		}
 		principal := model.Principal{
@@ -40,6 +61,7 @@ func Auth(parser *auth.Parser) gin.HandlerFunc {
 			DriverID: claims.DriverID,
 		}
 		c.Set(principalContextKey, principal)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(tracing.PrincipalAttributes(principal)...)
 		c.Next()
 	}
 }
@@ -0,0 +1,12 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"violation-service/internal/service"
+)
+
+// abortWithError отвечает в том же формате, что и обработчики: {"error": {code, message, fields}}.
+func abortWithError(c *gin.Context, err *service.Error) {
+	c.AbortWithStatusJSON(err.Status, gin.H{"error": err})
+}
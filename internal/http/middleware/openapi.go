@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/gin-gonic/gin"
+
+	"violation-service/internal/logger"
+	"violation-service/internal/openapi"
+	"violation-service/internal/service"
+)
+
+// ValidateRequest проверяет параметры, заголовки и тело запроса по спецификации
+// до вызова обработчика. Токен проверяет Auth, поэтому схема безопасности здесь
+// не применяется. Маршрут без операции в спецификации отклоняется с 500.
+func ValidateRequest(spec *openapi.Spec) gin.HandlerFunc {
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+	return func(c *gin.Context) {
+		route, ok := spec.Route(c.Request.Method, c.FullPath())
+		if !ok {
+			// NewRouter не поднимается с неописанными маршрутами, так что сюда попадает
+			// только ошибка сборки роутера; пропускать запрос без проверки нельзя.
+			logger.FromContext(c.Request.Context()).Error().
+				Str("route", c.FullPath()).
+				Msg("route has no OpenAPI operation, request rejected")
+			abortWithError(c, service.ErrInternal)
+			return
+		}
+		// Обработчики читают тело как JSON независимо от заголовка; клиенты, которые
+		// его не шлют, продолжают работать.
+		if route.Operation.RequestBody != nil && c.Request.ContentLength != 0 && c.GetHeader("Content-Type") == "" {
+			c.Request.Header.Set("Content-Type", "application/json")
+		}
+		params := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			params[param.Key] = param.Value
+		}
+
+		err := openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: params,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			abortWithError(c, validationError(err))
+			return
+		}
+		c.Next()
+	}
+}
+
+// validationError переводит ошибку openapi3filter в ошибку ввода с указанием поля:
+// для параметра — его имя, для тела — путь до свойства через точку.
+func validationError(err error) *service.Error {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return service.ErrInvalidInput.WithMessage(err.Error())
+	}
+
+	var schemaErr *openapi3.SchemaError
+	hasSchemaErr := errors.As(requestErr.Err, &schemaErr)
+	switch {
+	case requestErr.Parameter != nil:
+		reason := requestErr.Reason
+		if hasSchemaErr {
+			reason = schemaErr.Reason
+		} else if requestErr.Err != nil {
+			reason = requestErr.Err.Error()
+		}
+		return service.InvalidField(requestErr.Parameter.Name, reason)
+	case requestErr.RequestBody != nil && hasSchemaErr && len(schemaErr.JSONPointer()) > 0:
+		return service.InvalidField(strings.Join(schemaErr.JSONPointer(), "."), schemaErr.Reason)
+	default:
+		return service.ErrInvalidInput.WithMessage(requestErr.Error())
+	}
+}
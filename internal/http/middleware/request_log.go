@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+
+	"violation-service/internal/logger"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// RequestLog присваивает запросу X-Request-ID (или принимает его от вызывающего),
+// кладёт в контекст логгер с этим ID и после ответа пишет строку access-лога.
+// Для путей, на которых skip возвращает true, строка не пишется: пробы и scrape
+// приходят каждые несколько секунд и забивают лог.
+func RequestLog(base zerolog.Logger, skip func(path string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(requestIDHeader, requestID)
+
+		fields := base.With().Str("request_id", requestID)
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.HasTraceID() {
+			fields = fields.Str("trace_id", span.TraceID().String())
+		}
+		log := fields.Logger()
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), log))
+
+		c.Next()
+
+		if skip != nil && skip(c.Request.URL.Path) {
+			return
+		}
+		status := c.Writer.Status()
+		event := log.Info()
+		if status >= 500 {
+			event = log.Error()
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		event = event.
+			Str("method", c.Request.Method).
+			Str("route", route).
+			Str("path", c.Request.URL.Path).
+			Int("status", status).
+			Dur("latency_ms", time.Since(start)).
+			Int("bytes", c.Writer.Size()).
+			Str("client_ip", c.ClientIP())
+		if principal, ok := MustPrincipal(c); ok {
+			event = event.
+				Str("user_id", principal.UserID.String()).
+				Str("role", string(principal.Role)).
+				Str("org_id", principal.OrgID.String())
+		}
+		event.Msg("request")
+	}
+}
+
+// validRequestID пропускает только короткие ID из безопасных символов: значение
+// попадает в логи и заголовок ответа как есть.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
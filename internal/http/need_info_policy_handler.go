@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"violation-service/internal/http/middleware"
+)
+
+type needInfoPolicyPayload struct {
+	WindowHours int `json:"window_hours" binding:"required"`
+}
+
+func (h *Handler) listNeedInfoPolicies(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	policies, err := h.needInfoPolicyService.List(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(policies))
+}
+
+func (h *Handler) putNeedInfoPolicy(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	orgID, err := uuid.Parse(strings.TrimSpace(c.Param("organization_id")))
+	if err != nil {
+		h.handleError(c, invalidUUID("organization_id"))
+		return
+	}
+
+	var payload needInfoPolicyPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	policy, err := h.needInfoPolicyService.Put(c.Request.Context(), principal, orgID, payload.WindowHours)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(policy))
+}
+
+func (h *Handler) deleteNeedInfoPolicy(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	orgID, err := uuid.Parse(strings.TrimSpace(c.Param("organization_id")))
+	if err != nil {
+		h.handleError(c, invalidUUID("organization_id"))
+		return
+	}
+
+	if err := h.needInfoPolicyService.Delete(c.Request.Context(), principal, orgID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"status": "deleted"}))
+}
@@ -1,47 +1,162 @@
 package http
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/metrics"
+	"violation-service/internal/openapi"
+)
+
+const (
+	readinessTimeout = 3 * time.Second
+	serviceName      = "violation-service"
 )
 
-func NewRouter(handler *Handler, authMiddleware gin.HandlerFunc, env string) *gin.Engine {
+func isOperationalPath(path string) bool {
+	return path == "/healthz" || path == "/readyz" || path == "/metrics"
+}
+
+// ReadinessChecker сообщает, может ли экземпляр принимать трафик.
+type ReadinessChecker interface {
+	Ready(ctx context.Context) error
+}
+
+func NewRouter(handler *Handler, authMiddleware gin.HandlerFunc, readiness ReadinessChecker, spec *openapi.Spec, log zerolog.Logger, env string) (*gin.Engine, error) {
 	if env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
+	// Спан запроса открывается первым и продолжает входящий traceparent; пробы и
+	// /metrics не трассируются. Access-лог и Metrics снаружи Recovery, чтобы паника
+	// учитывалась как 500.
+	router.Use(otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !isOperationalPath(r.URL.Path)
+	})))
+	router.Use(middleware.RequestLog(log, isOperationalPath))
+	router.Use(middleware.Metrics())
 	router.Use(gin.Recovery())
 	router.Use(cors.New(cors.Config{
 		AllowAllOrigins: true,
 		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:    []string{"*"},
-		ExposeHeaders:   []string{"Content-Type"},
+		ExposeHeaders:   []string{"Content-Type", "Content-Disposition", "ETag", "X-Request-ID"},
 		MaxAge:          12 * time.Hour,
 	}))
 
 	router.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	router.GET("/readyz", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+		defer cancel()
+		if err := readiness.Ready(ctx); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	})
+
+	// Спецификация нужна фронтенду для генерации клиента, поэтому отдаётся без токена.
+	router.GET("/api/v1/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec.JSON())
+	})
 
 	// Префикс /api/v1 как в snowops-anpr-service (аналитика/reports)
 	protected := router.Group("/api/v1")
-	protected.Use(authMiddleware)
+	protected.Use(authMiddleware, middleware.ValidateRequest(spec))
 	{
 		protected.GET("/violations", handler.listViolations)
+		protected.GET("/violations/export", handler.exportViolations)
+		protected.GET("/violations/stats", handler.violationStats)
 		protected.GET("/violations/:id", handler.getViolation)
+		protected.GET("/violations/:id/history", handler.getViolationHistory)
+		protected.GET("/violations/:id/timeline", handler.getViolationTimeline)
 		protected.POST("/violations", handler.createViolation)
 		protected.PUT("/violations/:id/status", handler.updateViolationStatus)
+		protected.POST("/violations/:id/reopen", handler.reopenViolation)
 
 		protected.GET("/appeals", handler.listAppeals)
 		protected.GET("/appeals/:id", handler.getAppeal)
+		protected.GET("/appeals/:id/history", handler.getAppealHistory)
 		protected.POST("/violations/:id/appeals", handler.createAppeal)
 		protected.POST("/appeals/:id/comments", handler.addAppealComment)
 		protected.POST("/appeals/:id/actions", handler.actOnAppeal)
+
+		protected.GET("/webhooks", handler.listWebhooks)
+		protected.POST("/webhooks", handler.createWebhook)
+		protected.GET("/webhooks/:id", handler.getWebhook)
+		protected.PUT("/webhooks/:id", handler.updateWebhook)
+		protected.DELETE("/webhooks/:id", handler.deleteWebhook)
+		protected.GET("/webhooks/:id/deliveries", handler.listWebhookDeliveries)
+		protected.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", handler.redeliverWebhook)
+
+		protected.GET("/need-info-policies", handler.listNeedInfoPolicies)
+		protected.PUT("/need-info-policies/:organization_id", handler.putNeedInfoPolicy)
+		protected.DELETE("/need-info-policies/:organization_id", handler.deleteNeedInfoPolicy)
+
+		protected.POST("/sessions/revoke", handler.revokeSessions)
+
+		protected.GET("/violation-types", handler.listViolationTypes)
+		protected.GET("/violation-types/:code", handler.getViolationType)
+		protected.POST("/violation-types", handler.createViolationType)
+		protected.PUT("/violation-types/:code", handler.updateViolationType)
+		protected.DELETE("/violation-types/:code", handler.deleteViolationType)
+
+		protected.GET("/trip-status-rules", handler.listTripStatusRules)
+		protected.GET("/trip-status-rules/history", handler.tripStatusRuleHistory)
+		protected.GET("/trip-status-rules/preview", handler.previewTripStatusRule)
+		protected.PUT("/trip-status-rules/:trip_status", handler.putTripStatusRule)
+		protected.DELETE("/trip-status-rules/:trip_status", handler.deleteTripStatusRule)
 	}
 
-	return router
+	if err := checkSpecCoverage(router, spec); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+// CheckRoutes собирает роутер без зависимостей и сверяет его со спецификацией, как это
+// делает NewRouter при старте; нужен check-config.
+func CheckRoutes(spec *openapi.Spec) error {
+	_, err := NewRouter(&Handler{}, func(c *gin.Context) { c.Next() }, nil, spec, zerolog.Nop(), "")
+	return err
+}
+
+// checkSpecCoverage не даёт спецификации разойтись с роутером: каждый маршрут /api/v1
+// должен быть в ней описан, а каждая описанная операция — зарегистрирована.
+func checkSpecCoverage(router *gin.Engine, spec *openapi.Spec) error {
+	registered := make(map[string]bool)
+	var undocumented []string
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if _, ok := spec.Route(route.Method, route.Path); !ok {
+			undocumented = append(undocumented, key)
+		}
+	}
+	var unregistered []string
+	for _, key := range spec.Operations() {
+		if !registered[key] {
+			unregistered = append(unregistered, key)
+		}
+	}
+	if len(undocumented) > 0 || len(unregistered) > 0 {
+		return fmt.Errorf("openapi spec is out of sync with the router: undocumented routes %v, unknown operations %v", undocumented, unregistered)
+	}
+	return nil
 }
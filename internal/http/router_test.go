@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/openapi"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func passThrough(c *gin.Context) { c.Next() }
+
+func newTestRouter(t *testing.T) (*gin.Engine, *openapi.Spec) {
+	t.Helper()
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load: %v", err)
+	}
+	router, err := NewRouter(&Handler{}, passThrough, nil, spec, zerolog.Nop(), "test")
+	if err != nil {
+		t.Fatalf("NewRouter: %v", err)
+	}
+	return router, spec
+}
+
+func TestEveryAPIRouteHasSpecOperation(t *testing.T) {
+	router, spec := newTestRouter(t)
+
+	checked := 0
+	for _, route := range router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		checked++
+		if _, ok := spec.Route(route.Method, route.Path); !ok {
+			t.Errorf("%s %s has no operation in openapi.yaml", route.Method, route.Path)
+		}
+	}
+	if checked == 0 {
+		t.Fatal("router has no /api/v1 routes")
+	}
+	if operations := len(spec.Operations()); operations != checked {
+		t.Errorf("spec has %d operations, router has %d /api/v1 routes", operations, checked)
+	}
+}
+
+func TestCheckSpecCoverageRejectsUndocumentedRoute(t *testing.T) {
+	router, spec := newTestRouter(t)
+	router.GET("/api/v1/undocumented", passThrough)
+
+	err := checkSpecCoverage(router, spec)
+	if err == nil || !strings.Contains(err.Error(), "GET /api/v1/undocumented") {
+		t.Fatalf("checkSpecCoverage = %v, want undocumented route error", err)
+	}
+}
+
+func TestValidateRequestRejectsRouteWithoutOperation(t *testing.T) {
+	router, spec := newTestRouter(t)
+	// Маршрут, добавленный в обход NewRouter, не должен обходить валидацию.
+	api := router.Group("/api/v1")
+	api.Use(middleware.ValidateRequest(spec))
+	called := false
+	api.GET("/undocumented", func(c *gin.Context) { called = true })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/undocumented", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if called {
+		t.Fatal("handler ran without request validation")
+	}
+}
+
+func TestValidateRequestChecksParameters(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/violations/not-a-uuid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400; body %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"field":"id"`) {
+		t.Errorf("body %s does not name the id field", rec.Body)
+	}
+}
+
+func TestCheckRoutes(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load: %v", err)
+	}
+	if err := CheckRoutes(spec); err != nil {
+		t.Fatalf("CheckRoutes: %v", err)
+	}
+}
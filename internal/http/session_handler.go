@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/service"
+)
+
+// revokeSessionsPayload: ровно одно из session_id (одна сессия) или user_id (все сессии пользователя).
+type revokeSessionsPayload struct {
+	SessionID *uuid.UUID `json:"session_id"`
+	UserID    *uuid.UUID `json:"user_id"`
+	Reason    string     `json:"reason"`
+}
+
+func (h *Handler) revokeSessions(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	var payload revokeSessionsPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+	if (payload.SessionID == nil) == (payload.UserID == nil) {
+		h.handleError(c, service.ErrInvalidInput.WithMessage("exactly one of session_id or user_id is required"))
+		return
+	}
+
+	if payload.SessionID != nil {
+		revocation, err := h.sessionService.RevokeSession(c.Request.Context(), principal, *payload.SessionID, payload.Reason)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, successResponse(revocation))
+		return
+	}
+
+	revocation, err := h.sessionService.RevokeUser(c.Request.Context(), principal, *payload.UserID, payload.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, successResponse(revocation))
+}
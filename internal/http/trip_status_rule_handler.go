@@ -0,0 +1,130 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/model"
+	"violation-service/internal/service"
+)
+
+type tripStatusRulePayload struct {
+	ViolationType string `json:"violation_type" binding:"required"`
+	DetectedBy    string `json:"detected_by" binding:"required"`
+	Severity      string `json:"severity"`
+	Enabled       *bool  `json:"enabled"`
+}
+
+func tripStatusParam(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+func (h *Handler) listTripStatusRules(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	rules, err := h.tripStatusRuleService.List(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(rules))
+}
+
+func (h *Handler) putTripStatusRule(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	var payload tripStatusRulePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	rule, err := h.tripStatusRuleService.Put(c.Request.Context(), principal, tripStatusParam(c.Param("trip_status")), service.TripStatusRuleInput{
+		ViolationType: model.ViolationType(strings.ToUpper(strings.TrimSpace(payload.ViolationType))),
+		DetectedBy:    model.ViolationDetectedBy(strings.ToUpper(strings.TrimSpace(payload.DetectedBy))),
+		Severity:      model.ViolationSeverity(strings.ToUpper(strings.TrimSpace(payload.Severity))),
+		Enabled:       payload.Enabled,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(rule))
+}
+
+func (h *Handler) deleteTripStatusRule(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	if err := h.tripStatusRuleService.Delete(c.Request.Context(), principal, tripStatusParam(c.Param("trip_status"))); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"status": "deleted"}))
+}
+
+func (h *Handler) tripStatusRuleHistory(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	limit := 0
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			h.handleError(c, service.InvalidField("limit", "must be a positive integer"))
+			return
+		}
+		limit = value
+	}
+
+	entries, err := h.tripStatusRuleService.History(c.Request.Context(), principal, tripStatusParam(c.Query("trip_status")), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(entries))
+}
+
+func (h *Handler) previewTripStatusRule(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	tripStatus := tripStatusParam(c.Query("trip_status"))
+	if tripStatus == "" {
+		h.handleError(c, service.InvalidField("trip_status", "is required"))
+		return
+	}
+
+	preview, err := h.tripStatusRuleService.Preview(c.Request.Context(), principal, tripStatus)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(preview))
+}
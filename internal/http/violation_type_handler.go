@@ -0,0 +1,133 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/model"
+	"violation-service/internal/service"
+)
+
+type violationTypePayload struct {
+	Code             string                      `json:"code"`
+	Names            map[string]string           `json:"names"`
+	DefaultSeverity  *string                     `json:"default_severity"`
+	DetectionSources []model.ViolationDetectedBy `json:"detection_sources"`
+	Appealable       *bool                       `json:"appealable"`
+	IsActive         *bool                       `json:"is_active"`
+}
+
+func (p violationTypePayload) input() service.ViolationTypeInput {
+	input := service.ViolationTypeInput{
+		Names:      p.Names,
+		Appealable: p.Appealable,
+		IsActive:   p.IsActive,
+	}
+	if p.DefaultSeverity != nil {
+		severity := model.ViolationSeverity(strings.ToUpper(strings.TrimSpace(*p.DefaultSeverity)))
+		input.DefaultSeverity = &severity
+	}
+	if p.DetectionSources != nil {
+		input.DetectionSources = make([]model.ViolationDetectedBy, 0, len(p.DetectionSources))
+		for _, source := range p.DetectionSources {
+			input.DetectionSources = append(input.DetectionSources, model.ViolationDetectedBy(strings.ToUpper(strings.TrimSpace(string(source)))))
+		}
+	}
+	return input
+}
+
+func violationTypeCode(value string) model.ViolationType {
+	return model.ViolationType(strings.ToUpper(strings.TrimSpace(value)))
+}
+
+func (h *Handler) listViolationTypes(c *gin.Context) {
+	if _, ok := middleware.MustPrincipal(c); !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	types, err := h.violationTypeService.List(c.Request.Context(), parseBoolQuery(c.Query("include_inactive")))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(types))
+}
+
+func (h *Handler) getViolationType(c *gin.Context) {
+	if _, ok := middleware.MustPrincipal(c); !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	definition, err := h.violationTypeService.Get(c.Request.Context(), violationTypeCode(c.Param("code")))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(definition))
+}
+
+func (h *Handler) createViolationType(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	var payload violationTypePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	definition, err := h.violationTypeService.Create(c.Request.Context(), principal, violationTypeCode(payload.Code), payload.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(definition))
+}
+
+func (h *Handler) updateViolationType(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	var payload violationTypePayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	definition, err := h.violationTypeService.Update(c.Request.Context(), principal, violationTypeCode(c.Param("code")), payload.input())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(definition))
+}
+
+func (h *Handler) deleteViolationType(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	if err := h.violationTypeService.Delete(c.Request.Context(), principal, violationTypeCode(c.Param("code"))); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"status": "deleted"}))
+}
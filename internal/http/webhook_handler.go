@@ -0,0 +1,204 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"violation-service/internal/http/middleware"
+	"violation-service/internal/model"
+	"violation-service/internal/service"
+)
+
+type webhookPayload struct {
+	URL          *string  `json:"url"`
+	EventTypes   []string `json:"event_types"`
+	IsActive     *bool    `json:"is_active"`
+	Secret       *string  `json:"secret"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+func (p webhookPayload) toInput() service.WebhookInput {
+	input := service.WebhookInput{
+		URL:          p.URL,
+		IsActive:     p.IsActive,
+		Secret:       p.Secret,
+		RotateSecret: p.RotateSecret,
+	}
+	if p.EventTypes != nil {
+		input.EventTypes = make([]model.EventType, 0, len(p.EventTypes))
+		for _, t := range p.EventTypes {
+			input.EventTypes = append(input.EventTypes, model.EventType(strings.ToLower(strings.TrimSpace(t))))
+		}
+	}
+	return input
+}
+
+func (h *Handler) listWebhooks(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	subs, err := h.webhookService.List(c.Request.Context(), principal)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"items": subs}))
+}
+
+func (h *Handler) getWebhook(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	sub, err := h.webhookService.Get(c.Request.Context(), principal, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(sub))
+}
+
+func (h *Handler) createWebhook(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	var req webhookPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	sub, err := h.webhookService.Create(c.Request.Context(), principal, req.toInput())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, successResponse(sub))
+}
+
+func (h *Handler) updateWebhook(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	var req webhookPayload
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.handleError(c, invalidBody(err))
+		return
+	}
+
+	sub, err := h.webhookService.Update(c.Request.Context(), principal, id, req.toInput())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(sub))
+}
+
+func (h *Handler) deleteWebhook(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), principal, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(gin.H{"status": "deleted"}))
+}
+
+func (h *Handler) listWebhookDeliveries(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+
+	opts := service.WebhookDeliveryListOptions{
+		Cursor: strings.TrimSpace(c.Query("cursor")),
+		Limit:  parseIntQuery(c.Query("limit")),
+	}
+	if statusParam := c.Query("status"); statusParam != "" {
+		for _, val := range splitCSV(statusParam) {
+			opts.Statuses = append(opts.Statuses, model.WebhookDeliveryStatus(strings.ToUpper(val)))
+		}
+	}
+
+	page, err := h.webhookService.ListDeliveries(c.Request.Context(), principal, id, opts)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, successResponse(page))
+}
+
+func (h *Handler) redeliverWebhook(c *gin.Context) {
+	principal, ok := middleware.MustPrincipal(c)
+	if !ok {
+		h.handleError(c, errPrincipalMissing)
+		return
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		h.handleError(c, errInvalidID)
+		return
+	}
+	deliveryID, err := uuid.Parse(strings.TrimSpace(c.Param("delivery_id")))
+	if err != nil {
+		h.handleError(c, invalidUUID("delivery_id"))
+		return
+	}
+
+	if err := h.webhookService.Redeliver(c.Request.Context(), principal, id, deliveryID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, successResponse(gin.H{"status": "queued"}))
+}
@@ -1,15 +1,29 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	"github.com/rs/zerolog"
 )
 
+// New создаёт логгер сервиса. Он же становится логгером по умолчанию для FromContext,
+// так что код вне HTTP-запроса (фоновые задачи, миграции) пишет в тот же вывод.
 func New(env string) zerolog.Logger {
 	log := zerolog.New(os.Stderr).With().Timestamp().Logger()
 	if env == "development" {
 		log = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
+	zerolog.DefaultContextLogger = &log
 	return log
 }
+
+// WithContext кладёт в ctx логгер запроса (с request_id и прочими полями).
+func WithContext(ctx context.Context, log zerolog.Logger) context.Context {
+	return log.WithContext(ctx)
+}
+
+// FromContext возвращает логгер запроса, а вне запроса — логгер по умолчанию из New.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	return zerolog.Ctx(ctx)
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry — собственный реестр сервиса вместо глобального: в /metrics попадает только
+// то, что зарегистрировано здесь явно.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route template, method and status code.",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"method", "route", "status"})
+
+	violationsManualCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "violations_manual_created_total",
+		Help: "Violations created through the API, by type and detection source.",
+	}, []string{"type", "detected_by"})
+
+	appealTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "appeal_transitions_total",
+		Help: "Appeal actions applied, by action and actor kind (user or system).",
+	}, []string{"action", "actor"})
+
+	dbSlowQueries = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "db_slow_queries_total",
+		Help: "Queries slower than the gorm slow query threshold.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		violationsManualCreated,
+		appealTransitions,
+		dbSlowQueries,
+	)
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+func ObserveHTTPRequest(method, route string, status int, elapsed time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(elapsed.Seconds())
+}
+
+// ViolationCreated считает нарушение, созданное через API, после коммита транзакции.
+// Нарушения триггера считает stateCollector по базе.
+func ViolationCreated(violationType, detectedBy string) {
+	violationsManualCreated.WithLabelValues(violationType, detectedBy).Inc()
+}
+
+func AppealTransitions(action, actor string, count int) {
+	appealTransitions.WithLabelValues(action, actor).Add(float64(count))
+}
+
+func SlowQuery() {
+	dbSlowQueries.Inc()
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/model"
+)
+
+const stateQueryTimeout = 5 * time.Second
+
+// ViolationCounter и AppealCounter опрашиваются при каждом scrape.
+type ViolationCounter interface {
+	CountOpenByType(ctx context.Context) (map[string]int64, error)
+	CountAutoCreated(ctx context.Context) ([]model.ViolationKindCount, error)
+}
+
+type AppealCounter interface {
+	CountActiveByStatus(ctx context.Context) (map[string]int64, error)
+}
+
+// stateCollector читает гейджи и счётчик созданных триггером нарушений из БД при
+// scrape, а не держит их в памяти: нарушения создают и триггеры БД, и другие реплики,
+// поэтому память экземпляра не знает настоящих значений.
+type stateCollector struct {
+	violations ViolationCounter
+	appeals    AppealCounter
+	log        zerolog.Logger
+
+	openViolations *prometheus.Desc
+	autoCreated    *prometheus.Desc
+	activeAppeals  *prometheus.Desc
+	scrapeSuccess  *prometheus.Desc
+}
+
+// RegisterDB добавляет статистику пула sql.DB и гейджи состояния.
+func RegisterDB(sqlDB *sql.DB, violations ViolationCounter, appeals AppealCounter, log zerolog.Logger) error {
+	if err := Registry.Register(collectors.NewDBStatsCollector(sqlDB, "violations")); err != nil {
+		return err
+	}
+	return Registry.Register(&stateCollector{
+		violations: violations,
+		appeals:    appeals,
+		log:        log,
+		openViolations: prometheus.NewDesc("violations_open",
+			"Violations in OPEN status, by type.", []string{"type"}, nil),
+		autoCreated: prometheus.NewDesc("violations_auto_created_total",
+			"Violations created by the trip status trigger, by type and detection source.", []string{"type", "detected_by"}, nil),
+		activeAppeals: prometheus.NewDesc("appeals_active",
+			"Appeals awaiting a decision, by status.", []string{"status"}, nil),
+		scrapeSuccess: prometheus.NewDesc("violations_state_scrape_success",
+			"1 if the violation and appeal gauges were read from the database.", nil, nil),
+	})
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openViolations
+	ch <- c.autoCreated
+	ch <- c.activeAppeals
+	ch <- c.scrapeSuccess
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), stateQueryTimeout)
+	defer cancel()
+
+	success := 1.0
+	open, err := c.violations.CountOpenByType(ctx)
+	if err != nil {
+		c.log.Warn().Err(err).Msg("metrics: count open violations failed")
+		success = 0
+	}
+	for violationType, count := range open {
+		ch <- prometheus.MustNewConstMetric(c.openViolations, prometheus.GaugeValue, float64(count), violationType)
+	}
+
+	autoCreated, err := c.violations.CountAutoCreated(ctx)
+	if err != nil {
+		c.log.Warn().Err(err).Msg("metrics: count trigger-created violations failed")
+		success = 0
+	}
+	for _, row := range autoCreated {
+		ch <- prometheus.MustNewConstMetric(c.autoCreated, prometheus.CounterValue, float64(row.Count), row.Type, row.DetectedBy)
+	}
+
+	active, err := c.appeals.CountActiveByStatus(ctx)
+	if err != nil {
+		c.log.Warn().Err(err).Msg("metrics: count active appeals failed")
+		success = 0
+	}
+	for status, count := range active {
+		ch <- prometheus.MustNewConstMetric(c.activeAppeals, prometheus.GaugeValue, float64(count), status)
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.scrapeSuccess, prometheus.GaugeValue, success)
+}
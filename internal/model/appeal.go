@@ -17,6 +17,22 @@ const (
 	AppealStatusClosed      AppealStatus = "CLOSED"
 )
 
+// IsActive — апелляция ещё ждёт решения.
+func (s AppealStatus) IsActive() bool {
+	return s == AppealStatusSubmitted || s == AppealStatusUnderReview || s == AppealStatusNeedInfo
+}
+
+// AwaitsReviewer — ход за КГУ/Акиматом, а не за водителем или подрядчиком.
+func (s AppealStatus) AwaitsReviewer() bool {
+	return s == AppealStatusSubmitted || s == AppealStatusUnderReview
+}
+
+var ActiveAppealStatuses = []AppealStatus{
+	AppealStatusSubmitted,
+	AppealStatusUnderReview,
+	AppealStatusNeedInfo,
+}
+
 type AppealReasonCode string
 
 const (
@@ -26,6 +42,15 @@ const (
 	AppealReasonOther           AppealReasonCode = "OTHER"
 )
 
+func IsKnownAppealReason(code AppealReasonCode) bool {
+	switch code {
+	case AppealReasonCameraError, AppealReasonTransitPath, AppealReasonWrongAssignment, AppealReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
 type AttachmentFileType string
 
 const (
@@ -35,19 +60,24 @@ const (
 )
 
 type Appeal struct {
-	ID           uuid.UUID        `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
-	ViolationID  uuid.UUID        `gorm:"type:uuid;not null" json:"violation_id"`
-	TripID       uuid.UUID        `gorm:"type:uuid;not null" json:"trip_id"`
-	TicketID     *uuid.UUID       `gorm:"type:uuid" json:"ticket_id"`
-	DriverID     *uuid.UUID       `gorm:"type:uuid" json:"driver_id"`
-	ContractorID *uuid.UUID       `gorm:"type:uuid" json:"contractor_id"`
-	ReasonCode   AppealReasonCode `gorm:"type:appeal_reason_code;not null" json:"reason_code"`
-	ReasonText   string           `gorm:"type:text;not null" json:"reason_text"`
-	Status       AppealStatus     `gorm:"type:appeal_status;not null;default:'SUBMITTED'" json:"status"`
-	ResolvedBy   *uuid.UUID       `gorm:"type:uuid" json:"resolved_by"`
-	ResolvedAt   *time.Time       `json:"resolved_at"`
-	CreatedAt    time.Time        `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	ID              uuid.UUID        `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ViolationID     uuid.UUID        `gorm:"type:uuid;not null" json:"violation_id"`
+	TripID          uuid.UUID        `gorm:"type:uuid;not null" json:"trip_id"`
+	TicketID        *uuid.UUID       `gorm:"type:uuid" json:"ticket_id"`
+	DriverID        *uuid.UUID       `gorm:"type:uuid" json:"driver_id"`
+	ContractorID    *uuid.UUID       `gorm:"type:uuid" json:"contractor_id"`
+	ReasonCode      AppealReasonCode `gorm:"type:appeal_reason_code;not null" json:"reason_code"`
+	ReasonText      string           `gorm:"type:text;not null" json:"reason_text"`
+	Status          AppealStatus     `gorm:"type:appeal_status;not null;default:'SUBMITTED'" json:"status"`
+	ResolvedBy      *uuid.UUID       `gorm:"type:uuid" json:"resolved_by"`
+	ResolvedAt      *time.Time       `json:"resolved_at"`
+	DueAt           *time.Time       `json:"due_at"`
+	OverdueAt       *time.Time       `json:"overdue_at"`
+	StatusChangedAt *time.Time       `gorm:"default:now()" json:"status_changed_at"`
+	RemindedAt      *time.Time       `json:"reminded_at"`
+	Version         int64            `gorm:"not null;default:1" json:"version"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
 
 	Violation   *Violation         `gorm:"foreignKey:ViolationID"`
 	Trip        *Trip              `gorm:"foreignKey:TripID"`
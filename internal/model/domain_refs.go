@@ -87,3 +87,15 @@ type Polygon struct {
 func (Polygon) TableName() string {
 	return "polygons"
 }
+
+type User struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Login          string     `gorm:"type:varchar(255)"`
+	Role           UserRole   `gorm:"type:varchar(32)"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid"`
+	DriverID       *uuid.UUID `gorm:"type:uuid"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ActorBrief struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Role UserRole  `json:"role"`
+}
+
+// ActorRoleSystem помечает изменения без пользователя: триггеры и фоновые задачи
+// пишут в журналы changed_by = NULL.
+const ActorRoleSystem UserRole = "SYSTEM"
+
+// SystemActor подставляется в историю вместо пустого changed_by.
+var SystemActor = ActorBrief{ID: uuid.Nil, Name: "system", Role: ActorRoleSystem}
+
+type ViolationStatusLogDTO struct {
+	ViolationStatusLog
+	Actor *ActorBrief `json:"actor"`
+}
+
+type AppealStatusLogDTO struct {
+	AppealStatusLog
+	Actor *ActorBrief `json:"actor"`
+}
+
+type TimelineEventType string
+
+const (
+	TimelineViolationStatus TimelineEventType = "VIOLATION_STATUS"
+	TimelineAppealSubmitted TimelineEventType = "APPEAL_SUBMITTED"
+	TimelineAppealStatus    TimelineEventType = "APPEAL_STATUS"
+	TimelineAppealComment   TimelineEventType = "APPEAL_COMMENT"
+	TimelineAttachment      TimelineEventType = "APPEAL_ATTACHMENT"
+)
+
+// TimelineEvent — одна запись объединённой хронологии нарушения.
+type TimelineEvent struct {
+	Type       TimelineEventType  `json:"type"`
+	At         time.Time          `json:"at"`
+	AppealID   *uuid.UUID         `json:"appeal_id,omitempty"`
+	Actor      *ActorBrief        `json:"actor"`
+	OldStatus  *string            `json:"old_status,omitempty"`
+	NewStatus  *string            `json:"new_status,omitempty"`
+	Note       string             `json:"note,omitempty"`
+	ReasonCode *AppealReasonCode  `json:"reason_code,omitempty"`
+	FileURL    string             `json:"file_url,omitempty"`
+	FileType   AttachmentFileType `json:"file_type,omitempty"`
+}
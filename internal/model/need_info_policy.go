@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NeedInfoPolicy задаёт для организации-подрядчика, сколько апелляция может ждать
+// ответа в NEED_INFO до напоминания и автоматического отклонения.
+type NeedInfoPolicy struct {
+	OrganizationID uuid.UUID  `gorm:"type:uuid;primaryKey" json:"organization_id"`
+	WindowHours    int        `gorm:"not null" json:"window_hours"`
+	UpdatedBy      *uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (NeedInfoPolicy) TableName() string {
+	return "need_info_policies"
+}
+
+type NeedInfoPolicies struct {
+	DefaultWindowHours float64          `json:"default_window_hours"`
+	Policies           []NeedInfoPolicy `json:"policies"`
+}
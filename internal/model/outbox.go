@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventViolationCreated       EventType = "violation.created"
+	EventViolationStatusChanged EventType = "violation.status_changed"
+	EventAppealCreated          EventType = "appeal.created"
+	EventAppealCommentAdded     EventType = "appeal.comment_added"
+	EventAppealStatusChanged    EventType = "appeal.status_changed"
+	EventAppealEscalated        EventType = "appeal.escalated"
+	EventAppealReminder         EventType = "appeal.need_info_reminder"
+)
+
+const (
+	AggregateViolation = "violation"
+	AggregateAppeal    = "appeal"
+)
+
+type OutboxEvent struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	AggregateType string          `gorm:"type:varchar(32);not null" json:"aggregate_type"`
+	AggregateID   uuid.UUID       `gorm:"type:uuid;not null" json:"aggregate_id"`
+	EventType     EventType       `gorm:"type:varchar(64);not null" json:"type"`
+	Payload       json.RawMessage `gorm:"type:jsonb;not null" json:"payload"`
+	Attempts      int             `gorm:"not null;default:0" json:"-"`
+	NextAttemptAt time.Time       `gorm:"not null;default:now()" json:"-"`
+	LastError     *string         `gorm:"type:text" json:"-"`
+	PublishedAt   *time.Time      `json:"-"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"occurred_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// EventPayload — тело доменного события; ключи совпадают с jsonb из trg_trips_auto_violation.
+type EventPayload struct {
+	ViolationID   uuid.UUID           `json:"violation_id"`
+	AppealID      *uuid.UUID          `json:"appeal_id,omitempty"`
+	TripID        uuid.UUID           `json:"trip_id"`
+	ContractorID  *uuid.UUID          `json:"contractor_id,omitempty"`
+	DriverID      *uuid.UUID          `json:"driver_id,omitempty"`
+	PolygonID     *uuid.UUID          `json:"polygon_id,omitempty"`
+	ViolationType ViolationType       `json:"violation_type,omitempty"`
+	DetectedBy    ViolationDetectedBy `json:"detected_by,omitempty"`
+	Severity      ViolationSeverity   `json:"severity,omitempty"`
+	ReasonCode    AppealReasonCode    `json:"reason_code,omitempty"`
+	OldStatus     *string             `json:"old_status,omitempty"`
+	Status        string              `json:"status,omitempty"`
+	Note          string              `json:"note,omitempty"`
+	ActorID       *uuid.UUID          `json:"actor_id,omitempty"`
+	DueAt         *time.Time          `json:"due_at,omitempty"`
+	EscalatedTo   string              `json:"escalated_to,omitempty"`
+
+	// CameraAppeal заполняет раскладка вебхуков для событий нарушения: есть ли у него
+	// апелляция CAMERA_ERROR. В сообщение не попадает.
+	CameraAppeal bool `json:"-"`
+}
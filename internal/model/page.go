@@ -0,0 +1,8 @@
+package model
+
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	HasMore    bool    `json:"has_more"`
+	Total      *int64  `json:"total,omitempty"`
+}
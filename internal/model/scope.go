@@ -10,6 +10,7 @@ const (
 	ScopeContractor ScopeType = "CONTRACTOR"
 	ScopeDriver     ScopeType = "DRIVER"
 	ScopeTechnical  ScopeType = "TECHNICAL"
+	ScopeLandfill   ScopeType = "LANDFILL"
 )
 
 type Scope struct {
@@ -18,6 +19,7 @@ type Scope struct {
 	ContractorIDs   []uuid.UUID
 	OrganizationIDs []uuid.UUID
 	DriverID        *uuid.UUID
+	PolygonIDs      []uuid.UUID
 	TechnicalOnly   bool
 }
 
@@ -38,3 +40,38 @@ func (s Scope) AllowsViolation(contractorID *uuid.UUID) bool {
 	}
 	return false
 }
+
+// AllowsEvent проверяет, увидел бы владелец области это событие через API. Технической
+// области, как и в списках, доступны только нарушения с апелляцией CAMERA_ERROR и сами
+// такие апелляции; для событий нарушения вызывающий заполняет payload.CameraAppeal.
+func (s Scope) AllowsEvent(payload EventPayload) bool {
+	switch s.Type {
+	case ScopeCity:
+		return true
+	case ScopeDriver:
+		return s.DriverID != nil && payload.DriverID != nil && *s.DriverID == *payload.DriverID
+	case ScopeLandfill:
+		if payload.PolygonID == nil {
+			return false
+		}
+		for _, id := range s.PolygonIDs {
+			if id == *payload.PolygonID {
+				return true
+			}
+		}
+		return false
+	case ScopeTechnical:
+		technical := payload.DetectedBy == ViolationDetectedByLpr ||
+			payload.DetectedBy == ViolationDetectedByVolume ||
+			payload.DetectedBy == ViolationDetectedBySystem
+		if !technical {
+			return false
+		}
+		if payload.AppealID != nil {
+			return payload.ReasonCode == AppealReasonCameraError
+		}
+		return payload.CameraAppeal
+	default:
+		return s.AllowsViolation(payload.ContractorID)
+	}
+}
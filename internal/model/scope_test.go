@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestTechnicalScopeAllowsEvent(t *testing.T) {
+	scope := Scope{Type: ScopeTechnical, TechnicalOnly: true}
+	appealID := uuid.New()
+
+	tests := []struct {
+		name    string
+		payload EventPayload
+		want    bool
+	}{
+		{
+			name:    "violation without camera appeal",
+			payload: EventPayload{DetectedBy: ViolationDetectedByLpr},
+		},
+		{
+			name:    "violation with camera appeal",
+			payload: EventPayload{DetectedBy: ViolationDetectedByLpr, CameraAppeal: true},
+			want:    true,
+		},
+		{
+			name:    "gps violation with camera appeal",
+			payload: EventPayload{DetectedBy: ViolationDetectedByGps, CameraAppeal: true},
+		},
+		{
+			name:    "camera appeal",
+			payload: EventPayload{AppealID: &appealID, DetectedBy: ViolationDetectedByVolume, ReasonCode: AppealReasonCameraError},
+			want:    true,
+		},
+		{
+			name:    "other appeal on a camera-appealed violation",
+			payload: EventPayload{AppealID: &appealID, DetectedBy: ViolationDetectedBySystem, ReasonCode: AppealReasonOther, CameraAppeal: true},
+		},
+		{
+			name:    "camera appeal on a gps violation",
+			payload: EventPayload{AppealID: &appealID, DetectedBy: ViolationDetectedByGps, ReasonCode: AppealReasonCameraError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scope.AllowsEvent(tt.payload); got != tt.want {
+				t.Errorf("AllowsEvent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsEventByOrganization(t *testing.T) {
+	contractor := uuid.New()
+	other := uuid.New()
+	driver := uuid.New()
+	polygon := uuid.New()
+
+	tests := []struct {
+		name    string
+		scope   Scope
+		payload EventPayload
+		want    bool
+	}{
+		{name: "city", scope: Scope{Type: ScopeCity}, payload: EventPayload{}, want: true},
+		{name: "own contractor", scope: Scope{Type: ScopeContractor, OrgID: &contractor}, payload: EventPayload{ContractorID: &contractor}, want: true},
+		{name: "foreign contractor", scope: Scope{Type: ScopeContractor, OrgID: &contractor}, payload: EventPayload{ContractorID: &other}},
+		{name: "kgu contractor", scope: Scope{Type: ScopeKgu, ContractorIDs: []uuid.UUID{contractor}}, payload: EventPayload{ContractorID: &contractor}, want: true},
+		{name: "kgu without contractor", scope: Scope{Type: ScopeKgu, ContractorIDs: []uuid.UUID{contractor}}, payload: EventPayload{}},
+		{name: "own driver", scope: Scope{Type: ScopeDriver, DriverID: &driver}, payload: EventPayload{DriverID: &driver}, want: true},
+		{name: "landfill polygon", scope: Scope{Type: ScopeLandfill, PolygonIDs: []uuid.UUID{polygon}}, payload: EventPayload{PolygonID: &polygon}, want: true},
+		{name: "landfill without polygon", scope: Scope{Type: ScopeLandfill, PolygonIDs: []uuid.UUID{polygon}}, payload: EventPayload{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.AllowsEvent(tt.payload); got != tt.want {
+				t.Errorf("AllowsEvent = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedSession — отозванная сессия: токены с этим sid больше не принимаются.
+type RevokedSession struct {
+	SessionID uuid.UUID  `gorm:"type:uuid;primaryKey" json:"session_id"`
+	Reason    string     `json:"reason,omitempty"`
+	RevokedBy *uuid.UUID `gorm:"type:uuid" json:"revoked_by"`
+	RevokedAt time.Time  `gorm:"autoCreateTime" json:"revoked_at"`
+}
+
+func (RevokedSession) TableName() string {
+	return "revoked_sessions"
+}
+
+// UserSessionRevocation отзывает все токены пользователя, выданные раньше RevokedBefore.
+// Токены после повторного входа снова действительны.
+type UserSessionRevocation struct {
+	UserID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	RevokedBefore time.Time  `gorm:"not null" json:"revoked_before"`
+	Reason        string     `json:"reason,omitempty"`
+	RevokedBy     *uuid.UUID `gorm:"type:uuid" json:"revoked_by"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (UserSessionRevocation) TableName() string {
+	return "user_session_revocations"
+}
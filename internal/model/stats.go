@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StatsDimension string
+
+const (
+	StatsByType         StatsDimension = "type"
+	StatsBySeverity     StatsDimension = "severity"
+	StatsByStatus       StatsDimension = "status"
+	StatsByDetectedBy   StatsDimension = "detected_by"
+	StatsByContractor   StatsDimension = "contractor"
+	StatsByCleaningArea StatsDimension = "cleaning_area"
+	StatsByPolygon      StatsDimension = "polygon"
+	StatsByDay          StatsDimension = "day"
+	StatsByWeek         StatsDimension = "week"
+	StatsByMonth        StatsDimension = "month"
+)
+
+func (d StatsDimension) IsPeriod() bool {
+	return d == StatsByDay || d == StatsByWeek || d == StatsByMonth
+}
+
+func IsKnownStatsDimension(d StatsDimension) bool {
+	switch d {
+	case StatsByType, StatsBySeverity, StatsByStatus, StatsByDetectedBy,
+		StatsByContractor, StatsByCleaningArea, StatsByPolygon,
+		StatsByDay, StatsByWeek, StatsByMonth:
+		return true
+	default:
+		return false
+	}
+}
+
+// ViolationStatsRow — одна группа агрегата. Заполнены только поля выбранных измерений.
+type ViolationStatsRow struct {
+	Type             *string    `json:"type,omitempty"`
+	Severity         *string    `json:"severity,omitempty"`
+	Status           *string    `json:"status,omitempty"`
+	DetectedBy       *string    `json:"detected_by,omitempty"`
+	ContractorID     *uuid.UUID `json:"contractor_id,omitempty"`
+	ContractorName   *string    `json:"contractor_name,omitempty"`
+	CleaningAreaID   *uuid.UUID `json:"cleaning_area_id,omitempty"`
+	CleaningAreaName *string    `json:"cleaning_area_name,omitempty"`
+	PolygonID        *uuid.UUID `json:"polygon_id,omitempty"`
+	PolygonName      *string    `json:"polygon_name,omitempty"`
+	Period           *time.Time `json:"period,omitempty"`
+
+	Total           int64 `json:"total"`
+	Open            int64 `json:"open"`
+	Canceled        int64 `json:"canceled"`
+	Fixed           int64 `json:"fixed"`
+	Appealed        int64 `json:"appealed"`
+	AppealsPending  int64 `json:"appeals_pending"`
+	AppealsApproved int64 `json:"appeals_approved"`
+	AppealsRejected int64 `json:"appeals_rejected"`
+
+	// Доли считаются в SQL; null, если знаменатель равен нулю.
+	AppealRate   *float64 `json:"appeal_rate"`
+	ApprovalRate *float64 `json:"approval_rate"`
+	RejectRate   *float64 `json:"reject_rate"`
+}
+
+type ViolationStats struct {
+	GroupBy []StatsDimension    `json:"group_by"`
+	Groups  []ViolationStatsRow `json:"groups"`
+}
+
+// ViolationKindCount — число нарушений одного типа и источника обнаружения (для метрик).
+type ViolationKindCount struct {
+	Type       string
+	DetectedBy string
+	Count      int64
+}
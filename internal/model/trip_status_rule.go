@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTripStatusRule — статус-шаблон правила для статусов без собственного правила.
+const DefaultTripStatusRule = "*"
+
+// TripStatusRule описывает, какое нарушение триггер создаёт для статуса рейса.
+// Выключенное правило подавляет автосоздание для статуса.
+type TripStatusRule struct {
+	TripStatus    string              `gorm:"type:varchar(64);primaryKey" json:"trip_status"`
+	ViolationType ViolationType       `gorm:"type:varchar(64);not null" json:"violation_type"`
+	DetectedBy    ViolationDetectedBy `gorm:"type:violation_detected_by;not null" json:"detected_by"`
+	Severity      ViolationSeverity   `gorm:"type:violation_severity;not null" json:"severity"`
+	Enabled       bool                `gorm:"not null" json:"enabled"`
+	UpdatedBy     *uuid.UUID          `gorm:"type:uuid" json:"updated_by"`
+	CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TripStatusRule) TableName() string {
+	return "trip_status_violation_rules"
+}
+
+type TripStatusRuleAction string
+
+const (
+	TripStatusRuleCreated TripStatusRuleAction = "CREATED"
+	TripStatusRuleUpdated TripStatusRuleAction = "UPDATED"
+	TripStatusRuleDeleted TripStatusRuleAction = "DELETED"
+)
+
+// TripStatusRuleLog — запись журнала изменений правил со снимками до и после.
+type TripStatusRuleLog struct {
+	ID         uuid.UUID            `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TripStatus string               `gorm:"type:varchar(64);not null" json:"trip_status"`
+	Action     TripStatusRuleAction `gorm:"type:varchar(16);not null" json:"action"`
+	OldRule    json.RawMessage      `gorm:"type:jsonb" json:"old_rule"`
+	NewRule    json.RawMessage      `gorm:"type:jsonb" json:"new_rule"`
+	ChangedBy  *uuid.UUID           `gorm:"type:uuid" json:"changed_by"`
+	CreatedAt  time.Time            `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (TripStatusRuleLog) TableName() string {
+	return "trip_status_violation_rule_log"
+}
+
+// TripStatusRuleLogDTO дополняет запись журнала сведениями об авторе.
+type TripStatusRuleLogDTO struct {
+	TripStatusRuleLog
+	Actor *ActorBrief `json:"actor"`
+}
+
+// TripStatusPreview — результат пробного прогона: что триггер сделает для статуса.
+type TripStatusPreview struct {
+	TripStatus       string              `json:"trip_status"`
+	MatchedRule      *TripStatusRule     `json:"matched_rule"`
+	CreatesViolation bool                `json:"creates_violation"`
+	Violation        *PreviewedViolation `json:"violation,omitempty"`
+	ViolationReason  *string             `json:"violation_reason,omitempty"`
+}
+
+type PreviewedViolation struct {
+	Type       ViolationType       `json:"type"`
+	DetectedBy ViolationDetectedBy `json:"detected_by"`
+	Severity   ViolationSeverity   `json:"severity"`
+}
@@ -51,6 +51,7 @@ type Violation struct {
 	Severity    ViolationSeverity   `gorm:"type:violation_severity;not null" json:"severity"`
 	Status      ViolationStatus     `gorm:"type:violation_status;not null;default:'OPEN'" json:"status"`
 	Description string              `gorm:"type:text" json:"description"`
+	Version     int64               `gorm:"not null;default:1" json:"version"`
 	CreatedAt   time.Time           `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt   time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
 
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// KnownSeverities и KnownDetectionSources повторяют enum-ы violation_severity и
+// violation_detected_by; по ним проверяется ввод до обращения к БД.
+var (
+	KnownSeverities       = []ViolationSeverity{ViolationSeverityLow, ViolationSeverityMedium, ViolationSeverityHigh}
+	KnownDetectionSources = []ViolationDetectedBy{ViolationDetectedByLpr, ViolationDetectedByVolume, ViolationDetectedByGps, ViolationDetectedBySystem}
+)
+
+var violationTypeCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+func IsValidViolationTypeCode(code ViolationType) bool {
+	return violationTypeCodePattern.MatchString(string(code))
+}
+
+// LocalizedNames — названия по коду языка ("ru", "kk", "en"), хранятся в jsonb.
+type LocalizedNames map[string]string
+
+func (n LocalizedNames) Value() (driver.Value, error) {
+	if n == nil {
+		return "{}", nil
+	}
+	raw, err := json.Marshal(map[string]string(n))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (n *LocalizedNames) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, n)
+	case string:
+		return json.Unmarshal([]byte(v), n)
+	case nil:
+		*n = nil
+		return nil
+	default:
+		return errors.New("unsupported names value")
+	}
+}
+
+// DetectionSourceList хранится в jsonb-колонке как массив строк.
+type DetectionSourceList []ViolationDetectedBy
+
+func (l DetectionSourceList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	raw, err := json.Marshal([]ViolationDetectedBy(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (l *DetectionSourceList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return errors.New("unsupported detection_sources value")
+	}
+}
+
+func (l DetectionSourceList) Contains(value ViolationDetectedBy) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ViolationTypeDefinition — запись справочника типов нарушений. Пустой DetectionSources
+// разрешает любой источник; неактивный тип нельзя выбрать при ручном создании.
+type ViolationTypeDefinition struct {
+	Code             ViolationType       `gorm:"type:varchar(64);primaryKey" json:"code"`
+	Names            LocalizedNames      `gorm:"type:jsonb;not null" json:"names"`
+	DefaultSeverity  ViolationSeverity   `gorm:"type:violation_severity;not null" json:"default_severity"`
+	DetectionSources DetectionSourceList `gorm:"type:jsonb;not null" json:"detection_sources"`
+	Appealable       bool                `gorm:"not null" json:"appealable"`
+	IsActive         bool                `gorm:"not null" json:"is_active"`
+	CreatedAt        time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ViolationTypeDefinition) TableName() string {
+	return "violation_types"
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "DEAD"
+)
+
+// KnownEventTypes перечисляет события, на которые можно подписаться.
+var KnownEventTypes = []EventType{
+	EventViolationCreated,
+	EventViolationStatusChanged,
+	EventAppealCreated,
+	EventAppealCommentAdded,
+	EventAppealStatusChanged,
+	EventAppealEscalated,
+	EventAppealReminder,
+}
+
+func IsKnownEventType(value EventType) bool {
+	for _, known := range KnownEventTypes {
+		if known == value {
+			return true
+		}
+	}
+	return false
+}
+
+// EventTypeList хранится в jsonb-колонке как массив строк.
+type EventTypeList []EventType
+
+func (l EventTypeList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	raw, err := json.Marshal([]EventType(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (l *EventTypeList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+		return nil
+	default:
+		return errors.New("unsupported event_types value")
+	}
+}
+
+func (l EventTypeList) Contains(value EventType) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookSubscription struct {
+	ID             uuid.UUID     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	OrganizationID uuid.UUID     `gorm:"type:uuid;not null" json:"organization_id"`
+	OwnerRole      UserRole      `gorm:"type:varchar(32);not null" json:"owner_role"`
+	URL            string        `gorm:"type:text;not null" json:"url"`
+	Secret         string        `gorm:"type:text;not null" json:"-"`
+	EventTypes     EventTypeList `gorm:"type:jsonb;not null" json:"event_types"`
+	IsActive       bool          `gorm:"not null;default:true" json:"is_active"`
+	CreatedBy      *uuid.UUID    `gorm:"type:uuid" json:"created_by"`
+	CreatedAt      time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SubscriptionID uuid.UUID             `gorm:"type:uuid;not null" json:"subscription_id"`
+	EventID        uuid.UUID             `gorm:"type:uuid;not null" json:"event_id"`
+	EventType      EventType             `gorm:"type:varchar(64);not null" json:"event_type"`
+	Payload        json.RawMessage       `gorm:"type:jsonb;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(16);not null;default:'PENDING'" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null;default:now()" json:"next_attempt_at"`
+	LastStatusCode *int                  `json:"last_status_code"`
+	LastError      *string               `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time             `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookSubscriptionWithSecret отдаётся только при создании и ротации секрета.
+type WebhookSubscriptionWithSecret struct {
+	WebhookSubscription
+	Secret string `json:"secret,omitempty"`
+}
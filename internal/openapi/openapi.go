@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/google/uuid"
+)
+
+//go:embed openapi.yaml
+var document []byte
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Форматы проверяются теми же функциями, что и в обработчиках: встроенный uuid
+// требует RFC 4122, а идентификаторы общей схемы и демо-данных не обязаны нести
+// версию и вариант.
+func init() {
+	openapi3.DefineStringFormatCallback("uuid", func(value string) error {
+		if _, err := uuid.Parse(value); err != nil {
+			return errors.New("invalid UUID")
+		}
+		return nil
+	})
+	openapi3.DefineStringFormatCallback("date-time", func(value string) error {
+		if _, err := time.Parse(time.RFC3339, value); err != nil {
+			return errors.New("expected RFC 3339 timestamp")
+		}
+		return nil
+	})
+	// Без этого сообщение об ошибке содержит дамп всей схемы.
+	openapi3.SchemaErrorDetailsDisabled = true
+}
+
+// Spec — спецификация API, встроенная в бинарник. Она же источник правды для
+// валидации запросов, поэтому маршрут без описания в ней не поднимется (см. NewRouter).
+type Spec struct {
+	doc    *openapi3.T
+	json   []byte
+	routes map[string]*routers.Route
+}
+
+// Load разбирает и проверяет встроенную спецификацию.
+func Load() (*Spec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("parse openapi spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{doc: doc, json: raw, routes: make(map[string]*routers.Route)}
+	for path, item := range doc.Paths.Map() {
+		for method, operation := range item.Operations() {
+			spec.routes[routeKey(method, ginPath(path))] = &routers.Route{
+				Spec:      doc,
+				Path:      path,
+				PathItem:  item,
+				Method:    method,
+				Operation: operation,
+			}
+		}
+	}
+	return spec, nil
+}
+
+// JSON — спецификация в виде, в котором её отдаёт /api/v1/openapi.json.
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+// Route находит операцию по методу и шаблону маршрута Gin (/api/v1/violations/:id).
+func (s *Spec) Route(method, ginPath string) (*routers.Route, bool) {
+	route, ok := s.routes[routeKey(method, ginPath)]
+	return route, ok
+}
+
+// Operations перечисляет описанные операции как "METHOD /gin/path", отсортированно.
+func (s *Spec) Operations() []string {
+	keys := make([]string, 0, len(s.routes))
+	for key := range s.routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ginPath переводит шаблон пути OpenAPI ({id}) в синтаксис Gin (:id).
+func ginPath(path string) string {
+	return pathParam.ReplaceAllString(path, ":$1")
+}
+
+func routeKey(method, ginPath string) string {
+	return method + " " + ginPath
+}
@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"violation-service/internal/model"
+)
+
+// Publisher доставляет событие во внешний приёмник. Ошибка означает, что событие
+// будет отправлено повторно.
+type Publisher interface {
+	Publish(ctx context.Context, event model.OutboxEvent) error
+}
+
+// WriterPublisher пишет события в поток как JSON Lines (stdout или файл).
+type WriterPublisher struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewWriterPublisher(out io.Writer) *WriterPublisher {
+	return &WriterPublisher{out: out}
+}
+
+// NewFilePublisher открывает файл на дозапись; пустой путь или "-" означает stdout.
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	if path == "" || path == "-" {
+		return NewWriterPublisher(os.Stdout), nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterPublisher(file), nil
+}
+
+func (p *WriterPublisher) Publish(_ context.Context, event model.OutboxEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.out.Write(append(line, '\n'))
+	return err
+}
+
+// HTTPPublisher отправляет событие POST-запросом; любой ответ кроме 2xx считается ошибкой.
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPPublisher(url string, timeout time.Duration) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID.String())
+	req.Header.Set("X-Event-Type", string(event.EventType))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("publisher endpoint responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiPublisher публикует событие во все приёмники по очереди; ошибка любого из них
+// приводит к повторной отправке события целиком, поэтому приёмники должны быть идемпотентны.
+type MultiPublisher []Publisher
+
+func (m MultiPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	for _, p := range m {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/model"
+	"violation-service/internal/repository"
+)
+
+type RelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	// ClaimLease — на сколько откладывается повторная выдача взятых событий, пока
+	// идёт публикация; должна покрывать отправку всей пачки.
+	ClaimLease time.Duration
+}
+
+// Relay периодически вычитывает outbox_events и доставляет их через Publisher.
+// Событие помечается отправленным только после успешной публикации, поэтому
+// доставка — как минимум один раз (в том числе повторно, если аренда истекла до
+// отметки); получатели дедуплицируют по id события.
+type Relay struct {
+	repo      *repository.OutboxRepository
+	publisher Publisher
+	cfg       RelayConfig
+	log       zerolog.Logger
+}
+
+func NewRelay(repo *repository.OutboxRepository, publisher Publisher, cfg RelayConfig, log zerolog.Logger) *Relay {
+	return &Relay{
+		repo:      repo,
+		publisher: publisher,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.drain(ctx); err != nil && ctx.Err() == nil {
+			r.log.Error().Err(err).Msg("outbox relay iteration failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// outcome — результат публикации одного события, который фиксируется после отправки пачки.
+type outcome struct {
+	event  model.OutboxEvent
+	err    error
+	nextAt time.Time
+}
+
+// drain отправляет пачки, пока очередь не опустеет. Публикация идёт вне транзакции:
+// медленный приёмник не держит блокировки строк и соединение пула.
+func (r *Relay) drain(ctx context.Context) error {
+	for ctx.Err() == nil {
+		events, err := r.repo.ClaimPending(ctx, r.cfg.BatchSize, r.cfg.ClaimLease)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		outcomes := make([]outcome, 0, len(events))
+		for _, event := range events {
+			result := outcome{event: event}
+			if result.err = r.publisher.Publish(ctx, event); result.err != nil {
+				result.nextAt = time.Now().Add(Backoff(event.Attempts+1, r.cfg.RetryBase, r.cfg.RetryMax))
+				r.log.Warn().Err(result.err).
+					Str("event_id", event.ID.String()).
+					Str("event_type", string(event.EventType)).
+					Int("attempt", event.Attempts+1).
+					Time("next_attempt_at", result.nextAt).
+					Msg("outbox publish failed")
+			}
+			outcomes = append(outcomes, result)
+		}
+
+		err = r.repo.Transaction(ctx, func(tx *repository.OutboxRepository) error {
+			for _, result := range outcomes {
+				if result.err != nil {
+					if err := tx.MarkFailed(ctx, result.event.ID, result.nextAt, result.err.Error()); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := tx.MarkPublished(ctx, result.event.ID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(events) < r.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// Backoff возвращает экспоненциальную задержку base·2^(attempt-1), ограниченную max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
@@ -0,0 +1,170 @@
+package policy
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+
+	"violation-service/internal/model"
+)
+
+type Action string
+
+const (
+	ViolationCreate    Action = "violation.create"
+	ViolationSetStatus Action = "violation.set_status"
+	ViolationReopen    Action = "violation.reopen"
+
+	AppealCreate      Action = "appeal.create"
+	AppealComment     Action = "appeal.comment"
+	AppealStartReview Action = "appeal.act.start_review"
+	AppealNeedInfo    Action = "appeal.act.need_info"
+	AppealApprove     Action = "appeal.act.approve"
+	AppealReject      Action = "appeal.act.reject"
+	AppealClose       Action = "appeal.act.close"
+
+	WebhookManage        Action = "webhook.manage"
+	NeedInfoPolicyManage Action = "need_info_policy.manage"
+	SessionRevoke        Action = "session.revoke"
+	ViolationTypeManage  Action = "violation_type.manage"
+	TripStatusRuleManage Action = "trip_status_rule.manage"
+)
+
+var knownActions = map[Action]bool{
+	ViolationCreate:      true,
+	ViolationSetStatus:   true,
+	ViolationReopen:      true,
+	AppealCreate:         true,
+	AppealComment:        true,
+	AppealStartReview:    true,
+	AppealNeedInfo:       true,
+	AppealApprove:        true,
+	AppealReject:         true,
+	AppealClose:          true,
+	WebhookManage:        true,
+	NeedInfoPolicyManage: true,
+	SessionRevoke:        true,
+	ViolationTypeManage:  true,
+	TripStatusRuleManage: true,
+}
+
+var knownRoles = map[model.UserRole]bool{
+	model.UserRoleAkimatAdmin:     true,
+	model.UserRoleAkimatUser:      true,
+	model.UserRoleKguZkhAdmin:     true,
+	model.UserRoleKguZkhUser:      true,
+	model.UserRoleTooAdmin:        true,
+	model.UserRoleLandfillAdmin:   true,
+	model.UserRoleLandfillUser:    true,
+	model.UserRoleContractorAdmin: true,
+	model.UserRoleDriver:          true,
+}
+
+//go:embed default.yaml
+var defaultPolicy []byte
+
+// Rule разрешает действие ролям Roles; непустой States дополнительно требует,
+// чтобы ресурс находился в одном из перечисленных состояний.
+type Rule struct {
+	Roles  []model.UserRole `yaml:"roles"`
+	States []string         `yaml:"states"`
+}
+
+type document struct {
+	Rules map[Action][]Rule `yaml:"rules"`
+}
+
+// Policy — неизменяемый набор правил; всё, что не разрешено явно, запрещено.
+type Policy struct {
+	rules map[Action][]Rule
+}
+
+// Default возвращает встроенную политику (internal/policy/default.yaml).
+func Default() *Policy {
+	p, err := Parse(defaultPolicy)
+	if err != nil {
+		panic(fmt.Sprintf("policy: invalid embedded default: %v", err))
+	}
+	return p
+}
+
+// Load читает политику из YAML-файла; пустой путь означает встроенную политику.
+func Load(path string) (*Policy, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse разбирает и проверяет политику: неизвестные действия и роли — ошибка,
+// чтобы опечатка в конфиге не превращалась в молчаливый запрет.
+func Parse(data []byte) (*Policy, error) {
+	var doc document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for action, rules := range doc.Rules {
+		if !knownActions[action] {
+			return nil, fmt.Errorf("unknown action %q", action)
+		}
+		for i, rule := range rules {
+			if len(rule.Roles) == 0 {
+				return nil, fmt.Errorf("%s: rule %d has no roles", action, i+1)
+			}
+			for _, role := range rule.Roles {
+				if !knownRoles[role] {
+					return nil, fmt.Errorf("%s: unknown role %q", action, role)
+				}
+			}
+		}
+	}
+	return &Policy{rules: doc.Rules}, nil
+}
+
+// Permits сообщает, есть ли у роли хоть одно правило на действие. Используется для
+// ранней проверки до загрузки ресурса.
+func (p *Policy) Permits(role model.UserRole, action Action) bool {
+	for _, rule := range p.rules[action] {
+		if hasRole(rule, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows проверяет действие над ресурсом в состоянии state ("" — состояние не применимо).
+func (p *Policy) Allows(role model.UserRole, action Action, state string) bool {
+	for _, rule := range p.rules[action] {
+		if !hasRole(rule, role) {
+			continue
+		}
+		if len(rule.States) == 0 {
+			return true
+		}
+		for _, allowed := range rule.States {
+			if allowed == state {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasRole(rule Rule, role model.UserRole) bool {
+	for _, r := range rule.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+
+	"violation-service/internal/model"
+)
+
+var allRoles = []model.UserRole{
+	model.UserRoleAkimatAdmin,
+	model.UserRoleAkimatUser,
+	model.UserRoleKguZkhAdmin,
+	model.UserRoleKguZkhUser,
+	model.UserRoleTooAdmin,
+	model.UserRoleLandfillAdmin,
+	model.UserRoleLandfillUser,
+	model.UserRoleContractorAdmin,
+	model.UserRoleDriver,
+}
+
+// defaultGrants повторяет таблицу политики по умолчанию из README.
+var defaultGrants = map[Action][]model.UserRole{
+	ViolationCreate:      {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	ViolationSetStatus:   {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	ViolationReopen:      {model.UserRoleAkimatAdmin},
+	AppealCreate:         {model.UserRoleDriver, model.UserRoleContractorAdmin},
+	AppealComment:        allRoles,
+	AppealStartReview:    {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	AppealNeedInfo:       {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	AppealApprove:        {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	AppealReject:         {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	AppealClose:          {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	WebhookManage:        {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin, model.UserRoleContractorAdmin},
+	NeedInfoPolicyManage: {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin},
+	SessionRevoke:        {model.UserRoleAkimatAdmin, model.UserRoleKguZkhAdmin, model.UserRoleContractorAdmin},
+	ViolationTypeManage:  {model.UserRoleAkimatAdmin},
+	TripStatusRuleManage: {model.UserRoleAkimatAdmin},
+}
+
+func TestDefaultPolicyGrants(t *testing.T) {
+	p := Default()
+
+	for action := range knownActions {
+		if _, ok := defaultGrants[action]; !ok {
+			t.Errorf("action %s is missing from the expected grants table", action)
+		}
+	}
+
+	for action, granted := range defaultGrants {
+		for _, role := range allRoles {
+			want := containsRole(granted, role)
+			if got := p.Permits(role, action); got != want {
+				t.Errorf("Permits(%s, %s) = %v, want %v", role, action, got, want)
+			}
+		}
+	}
+}
+
+func TestDefaultPolicyReopenStates(t *testing.T) {
+	p := Default()
+
+	tests := []struct {
+		state string
+		want  bool
+	}{
+		{state: string(model.ViolationStatusCanceled), want: true},
+		{state: string(model.ViolationStatusFixed), want: true},
+		{state: string(model.ViolationStatusOpen), want: false},
+		{state: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := p.Allows(model.UserRoleAkimatAdmin, ViolationReopen, tt.state); got != tt.want {
+			t.Errorf("Allows(AKIMAT_ADMIN, reopen, %q) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+	if p.Allows(model.UserRoleKguZkhAdmin, ViolationReopen, string(model.ViolationStatusCanceled)) {
+		t.Error("KGU_ZKH_ADMIN must not reopen violations")
+	}
+}
+
+func TestDenyByDefault(t *testing.T) {
+	p, err := Parse([]byte("rules: {}\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for action := range knownActions {
+		for _, role := range allRoles {
+			if p.Permits(role, action) || p.Allows(role, action, "") {
+				t.Errorf("empty policy allows %s to %s", role, action)
+			}
+		}
+	}
+}
+
+func TestAllowsStates(t *testing.T) {
+	p, err := Parse([]byte(`
+rules:
+  appeal.comment:
+    - roles: [DRIVER]
+      states: [SUBMITTED, NEED_INFO]
+    - roles: [AKIMAT_ADMIN]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		role  model.UserRole
+		state string
+		want  bool
+	}{
+		{role: model.UserRoleDriver, state: "SUBMITTED", want: true},
+		{role: model.UserRoleDriver, state: "NEED_INFO", want: true},
+		{role: model.UserRoleDriver, state: "UNDER_REVIEW", want: false},
+		{role: model.UserRoleDriver, state: "", want: false},
+		{role: model.UserRoleAkimatAdmin, state: "UNDER_REVIEW", want: true},
+		{role: model.UserRoleAkimatAdmin, state: "", want: true},
+		{role: model.UserRoleContractorAdmin, state: "SUBMITTED", want: false},
+	}
+	for _, tt := range tests {
+		if got := p.Allows(tt.role, AppealComment, tt.state); got != tt.want {
+			t.Errorf("Allows(%s, %q) = %v, want %v", tt.role, tt.state, got, tt.want)
+		}
+	}
+	if !p.Permits(model.UserRoleDriver, AppealComment) {
+		t.Error("Permits must ignore states")
+	}
+}
+
+func TestParseRejectsInvalidPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "unknown action",
+			yaml:    "rules:\n  violation.delete:\n    - roles: [AKIMAT_ADMIN]\n",
+			wantErr: `unknown action "violation.delete"`,
+		},
+		{
+			name:    "unknown role",
+			yaml:    "rules:\n  violation.create:\n    - roles: [SUPERUSER]\n",
+			wantErr: `unknown role "SUPERUSER"`,
+		},
+		{
+			name:    "rule without roles",
+			yaml:    "rules:\n  violation.create:\n    - states: [OPEN]\n",
+			wantErr: "rule 1 has no roles",
+		},
+		{
+			name:    "malformed yaml",
+			yaml:    "rules: [",
+			wantErr: "yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if err == nil {
+				t.Fatal("Parse succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadEmptyPathUsesDefault(t *testing.T) {
+	p, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !p.Permits(model.UserRoleAkimatAdmin, ViolationCreate) {
+		t.Error("default policy must let AKIMAT_ADMIN create violations")
+	}
+}
+
+func containsRole(roles []model.UserRole, role model.UserRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
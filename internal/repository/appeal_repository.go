@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"violation-service/internal/model"
 )
@@ -28,11 +29,53 @@ type AppealFilter struct {
 	ViolationID    *uuid.UUID
 	DateFrom       *time.Time
 	DateTo         *time.Time
+	Overdue        *bool
+	Cursor         *Cursor
+	WithTotal      bool
 	Limit          int
 	Offset         int
 }
 
-func (r *AppealRepository) List(ctx context.Context, filter AppealFilter) ([]model.Appeal, error) {
+func (r *AppealRepository) List(ctx context.Context, filter AppealFilter) (model.Page[model.Appeal], error) {
+	var total *int64
+	if filter.WithTotal {
+		var count int64
+		if err := r.filteredQuery(ctx, filter).Count(&count).Error; err != nil {
+			return model.Page[model.Appeal]{}, err
+		}
+		total = &count
+	}
+
+	limit := normalizeLimit(filter.Limit)
+	query := applyKeyset(r.filteredQuery(ctx, filter), "violation_appeals", filter.Cursor)
+	if filter.Cursor == nil && filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var appeals []model.Appeal
+	if err := query.
+		Limit(limit + 1).
+		Preload("Violation").
+		Preload("Violation.Trip").
+		Preload("Violation.Trip.Ticket").
+		Preload("Violation.Trip.Ticket.Contractor").
+		Preload("Violation.Trip.Ticket.CleaningArea").
+		Preload("Violation.Trip.Driver").
+		Preload("Violation.Trip.Vehicle").
+		Preload("Violation.Trip.Polygon").
+		Preload("Driver").
+		Find(&appeals).Error; err != nil {
+		return model.Page[model.Appeal]{}, err
+	}
+
+	page := buildPage(appeals, limit, func(a model.Appeal) Cursor {
+		return Cursor{CreatedAt: a.CreatedAt, ID: a.ID}
+	})
+	page.Total = total
+	return page, nil
+}
+
+func (r *AppealRepository) filteredQuery(ctx context.Context, filter AppealFilter) *gorm.DB {
 	query := r.db.WithContext(ctx).
 		Model(&model.Appeal{}).
 		Joins("JOIN violations v ON v.id = violation_appeals.violation_id").
@@ -65,32 +108,16 @@ func (r *AppealRepository) List(ctx context.Context, filter AppealFilter) ([]mod
 	if filter.DateTo != nil {
 		query = query.Where("violation_appeals.created_at <= ?", *filter.DateTo)
 	}
-
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
-	}
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	} else {
-		query = query.Limit(200)
-	}
-
-	var appeals []model.Appeal
-	if err := query.Order("violation_appeals.created_at DESC").
-		Preload("Violation").
-		Preload("Violation.Trip").
-		Preload("Violation.Trip.Ticket").
-		Preload("Violation.Trip.Ticket.Contractor").
-		Preload("Violation.Trip.Ticket.CleaningArea").
-		Preload("Violation.Trip.Driver").
-		Preload("Violation.Trip.Vehicle").
-		Preload("Violation.Trip.Polygon").
-		Preload("Driver").
-		Find(&appeals).Error; err != nil {
-		return nil, err
+	if filter.Overdue != nil {
+		overdue := "violation_appeals.status IN ? AND violation_appeals.due_at IS NOT NULL AND violation_appeals.due_at < NOW()"
+		if *filter.Overdue {
+			query = query.Where(overdue, model.ActiveAppealStatuses)
+		} else {
+			query = query.Not(overdue, model.ActiveAppealStatuses)
+		}
 	}
 
-	return appeals, nil
+	return query
 }
 
 func (r *AppealRepository) GetByID(ctx context.Context, scope model.Scope, id uuid.UUID) (*model.Appeal, error) {
@@ -149,7 +176,7 @@ func (r *AppealRepository) ListByViolationID(ctx context.Context, scope model.Sc
 func (r *AppealRepository) CreateAppeal(ctx context.Context, appeal *model.Appeal, attachments []model.AppealAttachment, comment *model.AppealComment) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(appeal).Error; err != nil {
-			return err
+			return translateAppealError(err)
 		}
 
 		if len(attachments) > 0 {
@@ -189,9 +216,16 @@ func (r *AppealRepository) AddComment(ctx context.Context, comment *model.Appeal
 	})
 }
 
-func (r *AppealRepository) UpdateStatus(ctx context.Context, appealID uuid.UUID, status model.AppealStatus, resolvedBy *uuid.UUID) error {
+// UpdateStatus обновляет статус, только если версия строки совпадает с expectedVersion.
+// Срок SLA пересчитывается на новый статус, отметка о просрочке снимается.
+func (r *AppealRepository) UpdateStatus(ctx context.Context, appealID uuid.UUID, expectedVersion int64, status model.AppealStatus, resolvedBy *uuid.UUID, dueAt *time.Time) error {
 	data := map[string]interface{}{
-		"status": status,
+		"status":            status,
+		"status_changed_at": gorm.Expr("NOW()"),
+		"due_at":            dueAt,
+		"overdue_at":        gorm.Expr("NULL"),
+		"reminded_at":       gorm.Expr("NULL"),
+		"version":           gorm.Expr("version + 1"),
 	}
 	if status == model.AppealStatusApproved || status == model.AppealStatusRejected || status == model.AppealStatusClosed {
 		data["resolved_at"] = time.Now()
@@ -200,10 +234,103 @@ func (r *AppealRepository) UpdateStatus(ctx context.Context, appealID uuid.UUID,
 		data["resolved_at"] = gorm.Expr("NULL")
 		data["resolved_by"] = gorm.Expr("NULL")
 	}
+	result := r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("id = ? AND version = ?", appealID, expectedVersion).
+		Updates(data)
+	if result.Error != nil {
+		return translateAppealError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// LockOverdue блокирует ещё не помеченные апелляции с истёкшим сроком; занятые
+// другой транзакцией строки пропускаются.
+func (r *AppealRepository) LockOverdue(ctx context.Context, now time.Time, limit int) ([]model.Appeal, error) {
+	var appeals []model.Appeal
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status IN ? AND due_at <= ? AND overdue_at IS NULL", model.ActiveAppealStatuses, now).
+		Order("due_at ASC").
+		Limit(limit).
+		Find(&appeals).Error; err != nil {
+		return nil, err
+	}
+	return appeals, nil
+}
+
+// MarkOverdue ставит отметку о просрочке. Версия не меняется: отметка служебная
+// и не должна ломать If-Match у того, кто как раз рассматривает апелляцию.
+func (r *AppealRepository) MarkOverdue(ctx context.Context, appealID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("id = ?", appealID).
+		UpdateColumn("overdue_at", at).Error
+}
+
+// LockNeedInfoForReminder блокирует апелляции, ждущие ответа в NEED_INFO дольше окна
+// организации-подрядчика (или defaultWindow, если политика не задана) и ещё без напоминания.
+func (r *AppealRepository) LockNeedInfoForReminder(ctx context.Context, now time.Time, defaultWindow time.Duration, limit int) ([]model.Appeal, error) {
+	var appeals []model.Appeal
+	if err := r.db.WithContext(ctx).
+		Select("violation_appeals.*").
+		Joins("LEFT JOIN need_info_policies nip ON nip.organization_id = violation_appeals.contractor_id").
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "violation_appeals"}, Options: "SKIP LOCKED"}).
+		Where("violation_appeals.status = ? AND violation_appeals.reminded_at IS NULL", model.AppealStatusNeedInfo).
+		Where("violation_appeals.status_changed_at <= ?::timestamptz - make_interval(secs => COALESCE(nip.window_hours * 3600, ?))",
+			now, defaultWindow.Seconds()).
+		Order("violation_appeals.status_changed_at ASC").
+		Limit(limit).
+		Find(&appeals).Error; err != nil {
+		return nil, err
+	}
+	return appeals, nil
+}
+
+// LockNeedInfoForRejection блокирует апелляции, которые остались без ответа
+// спустя grace после напоминания.
+func (r *AppealRepository) LockNeedInfoForRejection(ctx context.Context, now time.Time, grace time.Duration, limit int) ([]model.Appeal, error) {
+	var appeals []model.Appeal
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND reminded_at IS NOT NULL AND reminded_at <= ?", model.AppealStatusNeedInfo, now.Add(-grace)).
+		Order("reminded_at ASC").
+		Limit(limit).
+		Find(&appeals).Error; err != nil {
+		return nil, err
+	}
+	return appeals, nil
+}
+
+// MarkReminded, как и MarkOverdue, не меняет версию строки.
+func (r *AppealRepository) MarkReminded(ctx context.Context, appealID uuid.UUID, at time.Time) error {
 	return r.db.WithContext(ctx).
 		Model(&model.Appeal{}).
 		Where("id = ?", appealID).
-		Updates(data).Error
+		UpdateColumn("reminded_at", at).Error
+}
+
+// ListMissingDueAt возвращает активные апелляции без срока в порядке keyset после cursor.
+func (r *AppealRepository) ListMissingDueAt(ctx context.Context, cursor *Cursor, limit int) ([]model.Appeal, error) {
+	query := r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("violation_appeals.status IN ? AND violation_appeals.due_at IS NULL", model.ActiveAppealStatuses)
+
+	var appeals []model.Appeal
+	if err := applyKeyset(query, "violation_appeals", cursor).Limit(limit).Find(&appeals).Error; err != nil {
+		return nil, err
+	}
+	return appeals, nil
+}
+
+func (r *AppealRepository) SetDueAt(ctx context.Context, appealID uuid.UUID, dueAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("id = ? AND due_at IS NULL", appealID).
+		UpdateColumn("due_at", dueAt).Error
 }
 
 func (r *AppealRepository) CountActiveByViolation(ctx context.Context, violationID uuid.UUID) (int64, error) {
@@ -221,6 +348,20 @@ func (r *AppealRepository) CountActiveByViolation(ctx context.Context, violation
 	return count, nil
 }
 
+// HasCameraAppeal сообщает, подавалась ли по нарушению апелляция CAMERA_ERROR; это
+// условие видимости нарушения для технической области.
+func (r *AppealRepository) HasCameraAppeal(ctx context.Context, violationID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Where("violation_id = ? AND reason_code = ?", violationID, model.AppealReasonCameraError).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 type AppealSummary struct {
 	LastAppeal *model.Appeal
 	HasActive  bool
@@ -265,3 +406,38 @@ func (r *AppealRepository) SummariesByViolationIDs(ctx context.Context, ids []uu
 func (r *AppealRepository) LogStatusChange(ctx context.Context, logEntry *model.AppealStatusLog) error {
 	return r.db.WithContext(ctx).Create(logEntry).Error
 }
+
+func (r *AppealRepository) ListStatusLog(ctx context.Context, appealIDs []uuid.UUID) ([]model.AppealStatusLog, error) {
+	logs := make([]model.AppealStatusLog, 0)
+	if len(appealIDs) == 0 {
+		return logs, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("appeal_id IN ?", appealIDs).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// CountActiveByStatus — число ожидающих решения апелляций по статусам (для метрик).
+func (r *AppealRepository) CountActiveByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []labelCount
+	if err := r.db.WithContext(ctx).
+		Model(&model.Appeal{}).
+		Select("status AS label, COUNT(*) AS count").
+		Where("status IN ?", model.ActiveAppealStatuses).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := labelCounts(rows)
+	// Нулевые статусы тоже выводим, чтобы ряды не пропадали из графиков.
+	for _, status := range model.ActiveAppealStatuses {
+		if _, ok := counts[string(status)]; !ok {
+			counts[string(status)] = 0
+		}
+	}
+	return counts, nil
+}
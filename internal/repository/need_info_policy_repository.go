@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"violation-service/internal/model"
+)
+
+type NeedInfoPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewNeedInfoPolicyRepository(db *gorm.DB) *NeedInfoPolicyRepository {
+	return &NeedInfoPolicyRepository{db: db}
+}
+
+// List возвращает политики; пустой orgIDs означает все организации.
+func (r *NeedInfoPolicyRepository) List(ctx context.Context, orgIDs []uuid.UUID) ([]model.NeedInfoPolicy, error) {
+	query := r.db.WithContext(ctx).Order("organization_id")
+	if orgIDs != nil {
+		if len(orgIDs) == 0 {
+			return []model.NeedInfoPolicy{}, nil
+		}
+		query = query.Where("organization_id IN ?", orgIDs)
+	}
+
+	policies := make([]model.NeedInfoPolicy, 0)
+	if err := query.Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *NeedInfoPolicyRepository) Get(ctx context.Context, orgID uuid.UUID) (*model.NeedInfoPolicy, error) {
+	var policy model.NeedInfoPolicy
+	if err := r.db.WithContext(ctx).First(&policy, "organization_id = ?", orgID).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Upsert создаёт или заменяет политику; неизвестная организация даёт gorm.ErrRecordNotFound.
+func (r *NeedInfoPolicyRepository) Upsert(ctx context.Context, policy *model.NeedInfoPolicy) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"window_hours", "updated_by", "updated_at"}),
+		}).
+		Create(policy).Error
+	if isForeignKeyViolation(err) {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *NeedInfoPolicyRepository) Delete(ctx context.Context, orgID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Delete(&model.NeedInfoPolicy{}, "organization_id = ?", orgID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+)
+
+const (
+	defaultPageLimit = 200
+	maxPageLimit     = 500
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor указывает на последнюю выданную запись в порядке (created_at DESC, id DESC).
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		return maxPageLimit
+	}
+	return limit
+}
+
+// applyKeyset ограничивает выборку записями строго после курсора и задаёт стабильный порядок.
+func applyKeyset(query *gorm.DB, table string, cursor *Cursor) *gorm.DB {
+	if cursor != nil {
+		query = query.Where(fmt.Sprintf("(%s.created_at, %s.id) < (?, ?)", table, table), cursor.CreatedAt, cursor.ID)
+	}
+	return query.Order(fmt.Sprintf("%s.created_at DESC, %s.id DESC", table, table))
+}
+
+// buildPage обрезает выборку limit+1 до limit и вычисляет курсор следующей страницы.
+func buildPage[T any](items []T, limit int, key func(T) Cursor) model.Page[T] {
+	page := model.Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.HasMore = true
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if page.HasMore {
+		next := key(page.Items[len(page.Items)-1]).Encode()
+		page.NextCursor = &next
+	}
+	return page
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func encodeRaw(raw string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := Cursor{
+		CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.FixedZone("ALMT", 5*3600)),
+		ID:        uuid.New(),
+	}
+
+	got, err := DecodeCursor(want.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("CreatedAt = %v, want %v", got.CreatedAt, want.CreatedAt)
+	}
+	if got.ID != want.ID {
+		t.Errorf("ID = %v, want %v", got.ID, want.ID)
+	}
+}
+
+func TestDecodeCursorRejectsMalformed(t *testing.T) {
+	id := uuid.New().String()
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "empty", value: ""},
+		{name: "bad base64", value: "not*base64!"},
+		{name: "missing separator", value: encodeRaw("2025-01-02T03:04:05Z" + id)},
+		{name: "bad time", value: encodeRaw("yesterday|" + id)},
+		{name: "bad uuid", value: encodeRaw("2025-01-02T03:04:05Z|not-a-uuid")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.value)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.value, err)
+			}
+			if cursor != nil {
+				t.Errorf("DecodeCursor(%q) = %+v, want nil", tt.value, cursor)
+			}
+		})
+	}
+}
+
+func TestNormalizeLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{limit: -1, want: defaultPageLimit},
+		{limit: 0, want: defaultPageLimit},
+		{limit: 1, want: 1},
+		{limit: maxPageLimit, want: maxPageLimit},
+		{limit: maxPageLimit + 1, want: maxPageLimit},
+	}
+	for _, tt := range tests {
+		if got := normalizeLimit(tt.limit); got != tt.want {
+			t.Errorf("normalizeLimit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+type pageRow struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func pageRowKey(row pageRow) Cursor {
+	return Cursor{CreatedAt: row.createdAt, ID: row.id}
+}
+
+func pageRows(n int) []pageRow {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := make([]pageRow, n)
+	for i := range rows {
+		rows[i] = pageRow{createdAt: base.Add(-time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	return rows
+}
+
+func TestBuildPage(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		limit     int
+		wantItems int
+		wantMore  bool
+	}{
+		{name: "empty", rows: 0, limit: 3, wantItems: 0},
+		{name: "under limit", rows: 2, limit: 3, wantItems: 2},
+		{name: "exactly limit", rows: 3, limit: 3, wantItems: 3},
+		{name: "limit plus one", rows: 4, limit: 3, wantItems: 3, wantMore: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rows []pageRow
+			if tt.rows > 0 {
+				rows = pageRows(tt.rows)
+			}
+			page := buildPage(rows, tt.limit, pageRowKey)
+
+			if page.Items == nil {
+				t.Fatal("Items is nil, want empty slice")
+			}
+			if len(page.Items) != tt.wantItems {
+				t.Errorf("len(Items) = %d, want %d", len(page.Items), tt.wantItems)
+			}
+			if page.HasMore != tt.wantMore {
+				t.Errorf("HasMore = %v, want %v", page.HasMore, tt.wantMore)
+			}
+			if !tt.wantMore {
+				if page.NextCursor != nil {
+					t.Errorf("NextCursor = %q, want nil", *page.NextCursor)
+				}
+				return
+			}
+			if page.NextCursor == nil {
+				t.Fatal("NextCursor is nil")
+			}
+			cursor, err := DecodeCursor(*page.NextCursor)
+			if err != nil {
+				t.Fatalf("DecodeCursor(NextCursor): %v", err)
+			}
+			last := page.Items[len(page.Items)-1]
+			if cursor.ID != last.id || !cursor.CreatedAt.Equal(last.createdAt) {
+				t.Errorf("NextCursor points at %+v, want last item %+v", cursor, last)
+			}
+		})
+	}
+}
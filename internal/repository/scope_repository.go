@@ -50,9 +50,20 @@ func (r *ScopeRepository) ResolveScope(ctx context.Context, principal model.Prin
 		scope.DriverID = principal.DriverID
 		return scope, nil
 	case principal.IsToo():
+		// TOO_ADMIN сохраняет прежнюю техническую область ради совместимости.
 		scope.Type = model.ScopeTechnical
 		scope.TechnicalOnly = true
 		return scope, nil
+	case principal.IsLandfill():
+		scope.Type = model.ScopeLandfill
+		scope.OrgID = &principal.OrgID
+		polygons, err := r.listPolygons(ctx, principal.OrgID)
+		if err != nil {
+			return model.Scope{}, err
+		}
+		scope.PolygonIDs = polygons
+		scope.OrganizationIDs = []uuid.UUID{principal.OrgID}
+		return scope, nil
 	default:
 		return model.Scope{}, ErrScopeUnsupported
 	}
@@ -76,3 +87,15 @@ func (r *ScopeRepository) listContractors(ctx context.Context, parent uuid.UUID)
 	}
 	return rows, nil
 }
+
+// listPolygons возвращает полигоны, принадлежащие организации полигона.
+func (r *ScopeRepository) listPolygons(ctx context.Context, owner uuid.UUID) ([]uuid.UUID, error) {
+	rows := make([]uuid.UUID, 0)
+	if err := r.db.WithContext(ctx).
+		Table("polygons").
+		Where("organization_id = ?", owner).
+		Pluck("id", &rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"violation-service/internal/model"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// RevokeSession идемпотентна: повторный отзыв сохраняет исходные время и причину.
+func (r *SessionRepository) RevokeSession(ctx context.Context, revocation *model.RevokedSession) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(revocation).Error
+}
+
+// RevokeUser сдвигает границу отзыва пользователя; неизвестный пользователь даёт gorm.ErrRecordNotFound.
+func (r *SessionRepository) RevokeUser(ctx context.Context, revocation *model.UserSessionRevocation) error {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"revoked_before", "reason", "revoked_by", "updated_at"}),
+		}).
+		Create(revocation).Error
+	if isForeignKeyViolation(err) {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+func (r *SessionRepository) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&model.RevokedSession{}).
+		Where("session_id = ?", sessionID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UserRevokedBefore возвращает границу отзыва пользователя или nil, если её нет.
+func (r *SessionRepository) UserRevokedBefore(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	var revocation model.UserSessionRevocation
+	err := r.db.WithContext(ctx).
+		Select("revoked_before").
+		First(&revocation, "user_id = ?", userID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revocation.RevokedBefore, nil
+}
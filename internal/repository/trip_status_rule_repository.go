@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"violation-service/internal/model"
+)
+
+type TripStatusRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewTripStatusRuleRepository(db *gorm.DB) *TripStatusRuleRepository {
+	return &TripStatusRuleRepository{db: db}
+}
+
+func (r *TripStatusRuleRepository) List(ctx context.Context) ([]model.TripStatusRule, error) {
+	rules := make([]model.TripStatusRule, 0)
+	if err := r.db.WithContext(ctx).Order("trip_status").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Find возвращает правило статуса или nil, если его нет.
+func (r *TripStatusRuleRepository) Find(ctx context.Context, tripStatus string) (*model.TripStatusRule, error) {
+	var rule model.TripStatusRule
+	err := r.db.WithContext(ctx).First(&rule, "trip_status = ?", tripStatus).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Upsert сохраняет правило и пишет в журнал снимки до и после в одной транзакции.
+// Строка блокируется, чтобы параллельные правки не перепутали снимки.
+func (r *TripStatusRuleRepository) Upsert(ctx context.Context, rule *model.TripStatusRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old model.TripStatusRule
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&old, "trip_status = ?", rule.TripStatus).Error
+		exists := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		action := model.TripStatusRuleCreated
+		if exists {
+			action = model.TripStatusRuleUpdated
+			rule.CreatedAt = old.CreatedAt
+			if err := tx.Save(rule).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Create(rule).Error; err != nil {
+			return err
+		}
+
+		entry := model.TripStatusRuleLog{TripStatus: rule.TripStatus, Action: action, ChangedBy: rule.UpdatedBy}
+		if exists {
+			if entry.OldRule, err = json.Marshal(old); err != nil {
+				return err
+			}
+		}
+		if entry.NewRule, err = json.Marshal(rule); err != nil {
+			return err
+		}
+		return tx.Create(&entry).Error
+	})
+}
+
+func (r *TripStatusRuleRepository) Delete(ctx context.Context, tripStatus string, actor uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old model.TripStatusRule
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&old, "trip_status = ?", tripStatus).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&old).Error; err != nil {
+			return err
+		}
+		snapshot, err := json.Marshal(old)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&model.TripStatusRuleLog{
+			TripStatus: tripStatus,
+			Action:     model.TripStatusRuleDeleted,
+			OldRule:    snapshot,
+			ChangedBy:  &actor,
+		}).Error
+	})
+}
+
+// ListLog возвращает журнал от новых записей к старым; пустой tripStatus — по всем статусам.
+func (r *TripStatusRuleRepository) ListLog(ctx context.Context, tripStatus string, limit int) ([]model.TripStatusRuleLog, error) {
+	query := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Limit(limit)
+	if tripStatus != "" {
+		query = query.Where("trip_status = ?", tripStatus)
+	}
+	entries := make([]model.TripStatusRuleLog, 0)
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Evaluate вызывает ту же функцию map_trip_status_to_violation, что и триггеры;
+// nil означает, что нарушение создано не будет.
+func (r *TripStatusRuleRepository) Evaluate(ctx context.Context, tripStatus string) (*model.PreviewedViolation, error) {
+	var rows []model.PreviewedViolation
+	if err := r.db.WithContext(ctx).
+		Raw("SELECT v_type AS type, v_detected AS detected_by, v_severity AS severity FROM map_trip_status_to_violation(?)", tripStatus).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+
+	constraintActiveAppeal = "uniq_violation_active_appeal"
+)
+
+var (
+	ErrActiveAppealExists = errors.New("violation already has an active appeal")
+	ErrVersionConflict    = errors.New("row version mismatch")
+)
+
+// Repos — набор репозиториев, привязанных к одной транзакции.
+type Repos struct {
+	Violations *ViolationRepository
+	Appeals    *AppealRepository
+	Outbox     *OutboxRepository
+}
+
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) *UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// Do выполняет fn в одной транзакции: любая ошибка откатывает все изменения.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(repos Repos) error) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repos{
+			Violations: NewViolationRepository(tx),
+			Appeals:    NewAppealRepository(tx),
+			Outbox:     NewOutboxRepository(tx),
+		})
+	})
+}
+
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == constraint
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation
+}
+
+func isForeignKeyViolationOn(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation && pgErr.ConstraintName == constraint
+}
+
+func translateAppealError(err error) error {
+	if isUniqueViolation(err, constraintActiveAppeal) {
+		return ErrActiveAppealExists
+	}
+	return err
+}
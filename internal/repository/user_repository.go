@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// ActorsByIDs возвращает краткие сведения о пользователях; для водителей имя берётся из drivers.
+func (r *UserRepository) ActorsByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]model.ActorBrief, error) {
+	result := make(map[uuid.UUID]model.ActorBrief)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	type actorRow struct {
+		ID   uuid.UUID
+		Name string
+		Role model.UserRole
+	}
+	var rows []actorRow
+	if err := r.db.WithContext(ctx).
+		Table("users u").
+		Select("u.id, COALESCE(d.full_name, u.login, '') AS name, u.role").
+		Joins("LEFT JOIN drivers d ON d.id = u.driver_id").
+		Where("u.id IN ?", ids).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.ID] = model.ActorBrief{ID: row.ID, Name: row.Name, Role: row.Role}
+	}
+	return result, nil
+}
+
+// OrganizationOf возвращает организацию пользователя; неизвестный пользователь даёт gorm.ErrRecordNotFound.
+func (r *UserRepository) OrganizationOf(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	var user model.User
+	if err := r.db.WithContext(ctx).Select("id, organization_id").First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return user.OrganizationID, nil
+}
@@ -19,23 +19,90 @@ func NewViolationRepository(db *gorm.DB) *ViolationRepository {
 }
 
 type ViolationFilter struct {
-	Scope          model.Scope
-	Statuses       []model.ViolationStatus
-	Types          []model.ViolationType
-	Severities     []model.ViolationSeverity
-	DetectedBy     []model.ViolationDetectedBy
-	ContractorIDs  []uuid.UUID
-	DriverID       *uuid.UUID
-	TicketID       *uuid.UUID
-	CleaningAreaID *uuid.UUID
-	DateFrom       *time.Time
-	DateTo         *time.Time
-	Search         string
-	Limit          int
-	Offset         int
-}
-
-func (r *ViolationRepository) List(ctx context.Context, filter ViolationFilter) ([]model.Violation, error) {
+	Scope               model.Scope
+	Statuses            []model.ViolationStatus
+	Types               []model.ViolationType
+	Severities          []model.ViolationSeverity
+	DetectedBy          []model.ViolationDetectedBy
+	ContractorIDs       []uuid.UUID
+	DriverID            *uuid.UUID
+	TicketID            *uuid.UUID
+	CleaningAreaID      *uuid.UUID
+	DateFrom            *time.Time
+	DateTo              *time.Time
+	Search              string
+	RequireCameraAppeal bool
+	Cursor              *Cursor
+	WithTotal           bool
+	Limit               int
+	Offset              int
+}
+
+func (r *ViolationRepository) List(ctx context.Context, filter ViolationFilter) (model.Page[model.Violation], error) {
+	var total *int64
+	if filter.WithTotal {
+		var count int64
+		if err := r.filteredQuery(ctx, filter).Count(&count).Error; err != nil {
+			return model.Page[model.Violation]{}, err
+		}
+		total = &count
+	}
+
+	limit := normalizeLimit(filter.Limit)
+	query := applyKeyset(r.filteredQuery(ctx, filter), "violations", filter.Cursor)
+	if filter.Cursor == nil && filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var violations []model.Violation
+	if err := preloadTripContext(query.Limit(limit + 1)).Find(&violations).Error; err != nil {
+		return model.Page[model.Violation]{}, err
+	}
+
+	page := buildPage(violations, limit, func(v model.Violation) Cursor {
+		return Cursor{CreatedAt: v.CreatedAt, ID: v.ID}
+	})
+	page.Total = total
+	return page, nil
+}
+
+// Stream обходит все подходящие нарушения пачками по batchSize через keyset,
+// не ограничивая общий объём и не держа всю выборку в памяти.
+func (r *ViolationRepository) Stream(ctx context.Context, filter ViolationFilter, batchSize int, fn func(batch []model.Violation) error) error {
+	var cursor *Cursor
+	for {
+		query := applyKeyset(r.filteredQuery(ctx, filter), "violations", cursor).Limit(batchSize)
+
+		var batch []model.Violation
+		if err := preloadTripContext(query).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		cursor = &Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func preloadTripContext(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Trip").
+		Preload("Trip.Ticket").
+		Preload("Trip.Ticket.Contractor").
+		Preload("Trip.Ticket.CleaningArea").
+		Preload("Trip.Driver").
+		Preload("Trip.Vehicle").
+		Preload("Trip.Polygon")
+}
+
+func (r *ViolationRepository) filteredQuery(ctx context.Context, filter ViolationFilter) *gorm.DB {
 	query := r.db.WithContext(ctx).
 		Model(&model.Violation{}).
 		Joins("JOIN trips t ON t.id = violations.trip_id").
@@ -79,31 +146,11 @@ func (r *ViolationRepository) List(ctx context.Context, filter ViolationFilter)
 			Joins("LEFT JOIN vehicles v ON v.id = t.vehicle_id").
 			Where("(d.full_name ILIKE ? OR v.plate_number ILIKE ?)", search, search)
 	}
-
-	if filter.Offset > 0 {
-		query = query.Offset(filter.Offset)
-	}
-	if filter.Limit > 0 {
-		query = query.Limit(filter.Limit)
-	} else {
-		query = query.Limit(200)
-	}
-
-	var violations []model.Violation
-	if err := query.
-		Order("violations.created_at DESC").
-		Preload("Trip").
-		Preload("Trip.Ticket").
-		Preload("Trip.Ticket.Contractor").
-		Preload("Trip.Ticket.CleaningArea").
-		Preload("Trip.Driver").
-		Preload("Trip.Vehicle").
-		Preload("Trip.Polygon").
-		Find(&violations).Error; err != nil {
-		return nil, err
+	if filter.RequireCameraAppeal {
+		query = query.Where("EXISTS (SELECT 1 FROM violation_appeals va WHERE va.violation_id = violations.id AND va.reason_code = ?)", model.AppealReasonCameraError)
 	}
 
-	return violations, nil
+	return query
 }
 
 func (r *ViolationRepository) GetByID(ctx context.Context, scope model.Scope, id uuid.UUID) (*model.Violation, error) {
@@ -131,24 +178,67 @@ func (r *ViolationRepository) GetByID(ctx context.Context, scope model.Scope, id
 	return &violation, nil
 }
 
+// Create сохраняет нарушение; тип, удалённый из каталога после проверки, даёт ErrUnknownViolationType.
 func (r *ViolationRepository) Create(ctx context.Context, violation *model.Violation) error {
-	return r.db.WithContext(ctx).Create(violation).Error
+	err := r.db.WithContext(ctx).Create(violation).Error
+	if isForeignKeyViolationOn(err, constraintViolationsType) {
+		return ErrUnknownViolationType
+	}
+	return err
 }
 
-func (r *ViolationRepository) UpdateStatus(ctx context.Context, violationID uuid.UUID, status model.ViolationStatus, description string) error {
-	return r.db.WithContext(ctx).
+// UpdateStatus обновляет статус, только если версия строки совпадает с expectedVersion.
+func (r *ViolationRepository) UpdateStatus(ctx context.Context, violationID uuid.UUID, expectedVersion int64, status model.ViolationStatus, description string) error {
+	result := r.db.WithContext(ctx).
 		Model(&model.Violation{}).
-		Where("id = ?", violationID).
+		Where("id = ? AND version = ?", violationID, expectedVersion).
 		Updates(map[string]interface{}{
 			"status":      status,
 			"description": description,
-		}).Error
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Reopen возвращает нарушение в OPEN, не трогая описание.
+func (r *ViolationRepository) Reopen(ctx context.Context, violationID uuid.UUID, expectedVersion int64) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.Violation{}).
+		Where("id = ? AND version = ?", violationID, expectedVersion).
+		Updates(map[string]interface{}{
+			"status":  model.ViolationStatusOpen,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
 func (r *ViolationRepository) LogStatusChange(ctx context.Context, logEntry *model.ViolationStatusLog) error {
 	return r.db.WithContext(ctx).Create(logEntry).Error
 }
 
+func (r *ViolationRepository) ListStatusLog(ctx context.Context, violationID uuid.UUID) ([]model.ViolationStatusLog, error) {
+	var logs []model.ViolationStatusLog
+	if err := r.db.WithContext(ctx).
+		Where("violation_id = ?", violationID).
+		Order("created_at ASC, id ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
 func (r *ViolationRepository) UpdateTripViolationReason(ctx context.Context, tripID uuid.UUID, reason string) error {
 	return r.db.WithContext(ctx).
 		Model(&model.Trip{}).
@@ -186,6 +276,11 @@ func applyScopeFilter(query *gorm.DB, scope model.Scope) *gorm.DB {
 			return query.Where("1=0")
 		}
 		return query.Where("t.driver_id = ?", *scope.DriverID)
+	case model.ScopeLandfill:
+		if len(scope.PolygonIDs) == 0 {
+			return query.Where("1=0")
+		}
+		return query.Where("t.polygon_id IN ?", scope.PolygonIDs)
 	case model.ScopeTechnical:
 		return query.Where("violations.detected_by IN ?", []model.ViolationDetectedBy{
 			model.ViolationDetectedByLpr,
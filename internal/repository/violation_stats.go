@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"violation-service/internal/model"
+)
+
+const periodExpr = "COALESCE(t.entry_at, violations.created_at) AT TIME ZONE 'UTC'"
+
+// Stats считает агрегаты по нарушениям целиком в SQL: группировка по выбранным измерениям
+// поверх тех же фильтров и области видимости, что и List.
+func (r *ViolationRepository) Stats(ctx context.Context, filter ViolationFilter, dims []model.StatsDimension) ([]model.ViolationStatsRow, error) {
+	query := r.filteredQuery(ctx, filter)
+
+	var columns []string
+	for _, dim := range dims {
+		switch dim {
+		case model.StatsByType:
+			columns = append(columns, "violations.type AS type")
+		case model.StatsBySeverity:
+			columns = append(columns, "violations.severity AS severity")
+		case model.StatsByStatus:
+			columns = append(columns, "violations.status AS status")
+		case model.StatsByDetectedBy:
+			columns = append(columns, "violations.detected_by AS detected_by")
+		case model.StatsByContractor:
+			query = query.Joins("LEFT JOIN organizations org ON org.id = tk.contractor_id")
+			columns = append(columns, "tk.contractor_id AS contractor_id", "org.name AS contractor_name")
+		case model.StatsByCleaningArea:
+			query = query.Joins("LEFT JOIN cleaning_areas ca ON ca.id = tk.cleaning_area_id")
+			columns = append(columns, "tk.cleaning_area_id AS cleaning_area_id", "ca.name AS cleaning_area_name")
+		case model.StatsByPolygon:
+			query = query.Joins("LEFT JOIN polygons pg ON pg.id = t.polygon_id")
+			columns = append(columns, "t.polygon_id AS polygon_id", "pg.name AS polygon_name")
+		case model.StatsByDay, model.StatsByWeek, model.StatsByMonth:
+			columns = append(columns, fmt.Sprintf("date_trunc('%s', %s) AS period", dim, periodExpr))
+		default:
+			return nil, fmt.Errorf("unknown stats dimension %q", dim)
+		}
+	}
+
+	// Измерения группируются по позиции: имена вроде status совпадают с колонками
+	// trips/tickets, и GROUP BY по псевдониму был бы неоднозначен.
+	positions := make([]string, 0, len(columns))
+	for i := range columns {
+		positions = append(positions, fmt.Sprintf("%d", i+1))
+	}
+
+	appealExists := "EXISTS (SELECT 1 FROM violation_appeals va WHERE va.violation_id = violations.id%s)"
+	aggregates := []string{
+		"COUNT(*) AS total",
+		fmt.Sprintf("COUNT(*) FILTER (WHERE violations.status = '%s') AS open", model.ViolationStatusOpen),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE violations.status = '%s') AS canceled", model.ViolationStatusCanceled),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE violations.status = '%s') AS fixed", model.ViolationStatusFixed),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE %s) AS appealed", fmt.Sprintf(appealExists, "")),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE %s) AS appeals_pending", fmt.Sprintf(appealExists,
+			fmt.Sprintf(" AND va.status IN ('%s', '%s', '%s')", model.AppealStatusSubmitted, model.AppealStatusUnderReview, model.AppealStatusNeedInfo))),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE %s) AS appeals_approved", fmt.Sprintf(appealExists,
+			fmt.Sprintf(" AND va.status = '%s'", model.AppealStatusApproved))),
+		fmt.Sprintf("COUNT(*) FILTER (WHERE %s) AS appeals_rejected", fmt.Sprintf(appealExists,
+			fmt.Sprintf(" AND va.status = '%s'", model.AppealStatusRejected))),
+	}
+
+	inner := query.Select(strings.Join(append(columns, aggregates...), ", "))
+	if len(positions) > 0 {
+		inner = inner.Group(strings.Join(positions, ", "))
+	}
+
+	outer := r.db.WithContext(ctx).
+		Table("(?) AS s", inner).
+		Select(`s.*,
+			ROUND(s.appealed::numeric / NULLIF(s.total, 0), 4)::float8 AS appeal_rate,
+			ROUND(s.appeals_approved::numeric / NULLIF(s.appeals_approved + s.appeals_rejected, 0), 4)::float8 AS approval_rate,
+			ROUND(s.appeals_rejected::numeric / NULLIF(s.appeals_approved + s.appeals_rejected, 0), 4)::float8 AS reject_rate`)
+	if len(positions) > 0 {
+		outer = outer.Order(strings.Join(positions, ", "))
+	}
+
+	var rows []model.ViolationStatsRow
+	if err := outer.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []model.ViolationStatsRow{}
+	}
+	return rows, nil
+}
+
+type labelCount struct {
+	Label string
+	Count int64
+}
+
+// CountOpenByType — число открытых нарушений по типам без учёта области видимости (для метрик).
+func (r *ViolationRepository) CountOpenByType(ctx context.Context) (map[string]int64, error) {
+	var rows []labelCount
+	if err := r.db.WithContext(ctx).
+		Model(&model.Violation{}).
+		Select("type AS label, COUNT(*) AS count").
+		Where("status = ?", model.ViolationStatusOpen).
+		Group("type").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return labelCounts(rows), nil
+}
+
+// CountAutoCreated — число нарушений, созданных триггером trg_trips_auto_violation, по типу
+// и источнику. Триггер сам увеличивает строку violation_auto_created_counters.
+func (r *ViolationRepository) CountAutoCreated(ctx context.Context) ([]model.ViolationKindCount, error) {
+	var rows []model.ViolationKindCount
+	err := r.db.WithContext(ctx).
+		Table("violation_auto_created_counters").
+		Select("type, detected_by, count").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func labelCounts(rows []labelCount) map[string]int64 {
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Label] = row.Count
+	}
+	return counts
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+)
+
+const (
+	constraintViolationTypesPkey = "violation_types_pkey"
+	constraintViolationsType     = "fk_violations_type"
+)
+
+var (
+	ErrViolationTypeExists  = errors.New("violation type already exists")
+	ErrViolationTypeInUse   = errors.New("violation type is referenced by violations")
+	ErrUnknownViolationType = errors.New("violation type does not exist")
+)
+
+type ViolationTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewViolationTypeRepository(db *gorm.DB) *ViolationTypeRepository {
+	return &ViolationTypeRepository{db: db}
+}
+
+func (r *ViolationTypeRepository) List(ctx context.Context, includeInactive bool) ([]model.ViolationTypeDefinition, error) {
+	query := r.db.WithContext(ctx).Order("code")
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+	types := make([]model.ViolationTypeDefinition, 0)
+	if err := query.Find(&types).Error; err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+func (r *ViolationTypeRepository) Get(ctx context.Context, code model.ViolationType) (*model.ViolationTypeDefinition, error) {
+	var definition model.ViolationTypeDefinition
+	if err := r.db.WithContext(ctx).First(&definition, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *ViolationTypeRepository) Create(ctx context.Context, definition *model.ViolationTypeDefinition) error {
+	err := r.db.WithContext(ctx).Create(definition).Error
+	if isUniqueViolation(err, constraintViolationTypesPkey) {
+		return ErrViolationTypeExists
+	}
+	return err
+}
+
+// Update перезаписывает изменяемые поля; код типа неизменен.
+func (r *ViolationTypeRepository) Update(ctx context.Context, definition *model.ViolationTypeDefinition) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.ViolationTypeDefinition{}).
+		Where("code = ?", definition.Code).
+		Updates(map[string]interface{}{
+			"names":             definition.Names,
+			"default_severity":  definition.DefaultSeverity,
+			"detection_sources": definition.DetectionSources,
+			"appealable":        definition.Appealable,
+			"is_active":         definition.IsActive,
+			"updated_at":        gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete удаляет только неиспользуемый тип; используемый остаётся доступен для деактивации.
+func (r *ViolationTypeRepository) Delete(ctx context.Context, code model.ViolationType) error {
+	result := r.db.WithContext(ctx).Delete(&model.ViolationTypeDefinition{}, "code = ?", code)
+	if isForeignKeyViolation(result.Error) {
+		return ErrViolationTypeInUse
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"violation-service/internal/model"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, orgID uuid.UUID) ([]model.WebhookSubscription, error) {
+	subs := make([]model.WebhookSubscription, 0)
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at DESC").
+		Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, orgID, id uuid.UUID) (*model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND organization_id = ?", id, orgID).
+		First(&sub).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, sub *model.WebhookSubscription) error {
+	return r.db.WithContext(ctx).
+		Model(sub).
+		Select("url", "secret", "event_types", "is_active").
+		Updates(sub).Error
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, orgID, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND organization_id = ?", id, orgID).
+		Delete(&model.WebhookSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) SubscriptionsByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]model.WebhookSubscription, error) {
+	result := make(map[uuid.UUID]model.WebhookSubscription)
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var subs []model.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		result[sub.ID] = sub
+	}
+	return result, nil
+}
+
+// ActiveSubscriptionsFor возвращает активные подписки на данный тип события.
+func (r *WebhookRepository) ActiveSubscriptionsFor(ctx context.Context, eventType model.EventType) ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ? AND jsonb_exists(event_types, ?)", true, string(eventType)).
+		Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// EnqueueDelivery ставит доставку в очередь; повторная постановка того же события игнорируется.
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(delivery).Error
+}
+
+type WebhookDeliveryFilter struct {
+	SubscriptionID uuid.UUID
+	Statuses       []model.WebhookDeliveryStatus
+	Cursor         *Cursor
+	Limit          int
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter) (model.Page[model.WebhookDelivery], error) {
+	limit := normalizeLimit(filter.Limit)
+	query := r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("webhook_deliveries.subscription_id = ?", filter.SubscriptionID)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("webhook_deliveries.status IN ?", filter.Statuses)
+	}
+	query = applyKeyset(query, "webhook_deliveries", filter.Cursor)
+
+	var deliveries []model.WebhookDelivery
+	if err := query.Limit(limit + 1).Find(&deliveries).Error; err != nil {
+		return model.Page[model.WebhookDelivery]{}, err
+	}
+	return buildPage(deliveries, limit, func(d model.WebhookDelivery) Cursor {
+		return Cursor{CreatedAt: d.CreatedAt, ID: d.ID}
+	}), nil
+}
+
+// Redeliver возвращает доставку в очередь с обнулённым счётчиком попыток.
+func (r *WebhookRepository) Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ? AND subscription_id = ?", deliveryID, subscriptionID).
+		Updates(map[string]interface{}{
+			"status":          model.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ClaimDue забирает до limit готовых к отправке доставок короткой транзакцией так же,
+// как OutboxRepository.ClaimPending: строки выбираются SKIP LOCKED, next_attempt_at
+// сдвигается на lease. Отправка идёт без транзакции; если воркер упадёт, доставки
+// вернутся в очередь по истечении аренды.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= NOW()", model.WebhookDeliveryPending).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		return tx.Model(&model.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           model.WebhookDeliveryDelivered,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_status_code": statusCode,
+			"last_error":       gorm.Expr("NULL"),
+			"delivered_at":     time.Now(),
+		}).Error
+}
+
+// MarkFailed фиксирует неудачную попытку; при dead=true доставка уходит в dead-letter.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id uuid.UUID, statusCode *int, reason string, nextAttemptAt time.Time, dead bool) error {
+	status := model.WebhookDeliveryPending
+	if dead {
+		status = model.WebhookDeliveryDead
+	}
+	return r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_status_code": statusCode,
+			"last_error":       reason,
+			"next_attempt_at":  nextAttemptAt,
+		}).Error
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"violation-service/internal/model"
+)
+
+func TestClaimDueHidesClaimedDeliveries(t *testing.T) {
+	ctx := context.Background()
+	queue, db := openDeliveryQueue(t)
+	due := queue.add(model.WebhookDeliveryPending, time.Now().Add(-time.Minute))
+	queue.add(model.WebhookDeliveryPending, time.Now().Add(time.Hour))
+	queue.add(model.WebhookDeliveryDelivered, time.Now().Add(-time.Minute))
+	repo := NewWebhookRepository(db)
+
+	claimed, err := repo.ClaimDue(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != due {
+		t.Fatalf("ClaimDue returned %v, want only %s", claimed, due)
+	}
+	if next := queue.nextAttempt(due); !next.After(time.Now()) {
+		t.Fatalf("next_attempt_at = %s, want it pushed past now by the lease", next)
+	}
+
+	again, err := repo.ClaimDue(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("second ClaimDue: %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("second ClaimDue returned %d deliveries while the lease is live, want 0", len(again))
+	}
+}
+
+func TestClaimDueReturnsDeliveryAfterLeaseExpires(t *testing.T) {
+	ctx := context.Background()
+	queue, db := openDeliveryQueue(t)
+	id := queue.add(model.WebhookDeliveryPending, time.Now().Add(-time.Minute))
+	repo := NewWebhookRepository(db)
+
+	if _, err := repo.ClaimDue(ctx, 10, -time.Second); err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	claimed, err := repo.ClaimDue(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("second ClaimDue: %v", err)
+	}
+	if len(claimed) != 1 || claimed[0].ID != id {
+		t.Fatalf("ClaimDue after an expired lease returned %v, want %s", claimed, id)
+	}
+}
+
+// deliveryQueue — драйвер database/sql, который держит webhook_deliveries в памяти
+// и понимает только запросы ClaimDue: выборку готовых доставок и сдвиг next_attempt_at.
+type deliveryQueue struct {
+	mu   sync.Mutex
+	rows map[string]*queuedDelivery
+}
+
+type queuedDelivery struct {
+	status        model.WebhookDeliveryStatus
+	nextAttemptAt time.Time
+}
+
+var deliveryQueueSeq atomic.Int64
+
+func openDeliveryQueue(t *testing.T) (*deliveryQueue, *gorm.DB) {
+	t.Helper()
+	queue := &deliveryQueue{rows: map[string]*queuedDelivery{}}
+	name := fmt.Sprintf("deliveryqueue-%d", deliveryQueueSeq.Add(1))
+	sql.Register(name, queue)
+	sqlDB, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("open gorm: %v", err)
+	}
+	return queue, db
+}
+
+func (q *deliveryQueue) add(status model.WebhookDeliveryStatus, nextAttemptAt time.Time) uuid.UUID {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	id := uuid.New()
+	q.rows[id.String()] = &queuedDelivery{status: status, nextAttemptAt: nextAttemptAt}
+	return id
+}
+
+func (q *deliveryQueue) nextAttempt(id uuid.UUID) time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.rows[id.String()].nextAttemptAt
+}
+
+func (q *deliveryQueue) Open(string) (driver.Conn, error) {
+	return &deliveryConn{queue: q}, nil
+}
+
+type deliveryConn struct {
+	queue *deliveryQueue
+}
+
+func (c *deliveryConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fake driver: prepared statements are not supported")
+}
+
+func (c *deliveryConn) Close() error              { return nil }
+func (c *deliveryConn) Begin() (driver.Tx, error) { return c, nil }
+func (c *deliveryConn) Commit() error             { return nil }
+func (c *deliveryConn) Rollback() error           { return nil }
+
+func (c *deliveryConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, `SELECT * FROM "webhook_deliveries"`) || !strings.Contains(query, "next_attempt_at <= NOW()") {
+		return nil, fmt.Errorf("fake driver: unexpected query %q", query)
+	}
+	c.queue.mu.Lock()
+	defer c.queue.mu.Unlock()
+	now := time.Now()
+	rows := &deliveryRows{}
+	for id, row := range c.queue.rows {
+		if string(row.status) == args[0].Value && !row.nextAttemptAt.After(now) {
+			rows.values = append(rows.values, []driver.Value{id, string(row.status), row.nextAttemptAt})
+		}
+	}
+	return rows, nil
+}
+
+func (c *deliveryConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.HasPrefix(query, `UPDATE "webhook_deliveries" SET "next_attempt_at"=`) {
+		return nil, fmt.Errorf("fake driver: unexpected statement %q", query)
+	}
+	c.queue.mu.Lock()
+	defer c.queue.mu.Unlock()
+	next := args[0].Value.(time.Time)
+	var affected int64
+	for _, arg := range args[1:] {
+		id, ok := arg.Value.(string)
+		if !ok {
+			continue
+		}
+		if row, found := c.queue.rows[id]; found {
+			row.nextAttemptAt = next
+			affected++
+		}
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+type deliveryRows struct {
+	values [][]driver.Value
+	next   int
+}
+
+func (r *deliveryRows) Columns() []string {
+	return []string{"id", "status", "next_attempt_at"}
+}
+
+func (r *deliveryRows) Close() error { return nil }
+
+func (r *deliveryRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
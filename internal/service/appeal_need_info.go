@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"violation-service/internal/metrics"
+	"violation-service/internal/model"
+	"violation-service/internal/repository"
+)
+
+const autoRejectNote = "auto-rejected: no answer to the information request"
+
+// RemindStaleNeedInfo отправляет единственное напоминание по апелляциям, которые ждут
+// ответа в NEED_INFO дольше окна организации. Напоминание — событие в outbox и заметка
+// в журнале статусов; grace — сколько ещё ждать до автоматического отклонения.
+func (s *AppealService) RemindStaleNeedInfo(ctx context.Context, now time.Time, defaultWindow, grace time.Duration, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "AppealService.RemindStaleNeedInfo")
+	defer span.End()
+
+	reminded := 0
+	err := s.uow.Do(ctx, func(repos repository.Repos) error {
+		appeals, err := repos.Appeals.LockNeedInfoForReminder(ctx, now, defaultWindow, limit)
+		if err != nil {
+			return err
+		}
+		for i := range appeals {
+			appeal := &appeals[i]
+			if err := repos.Appeals.MarkReminded(ctx, appeal.ID, now); err != nil {
+				return err
+			}
+			appeal.RemindedAt = &now
+
+			rejectAt := now.Add(grace)
+			status := appeal.Status
+			note := fmt.Sprintf("reminder sent: answer required before %s", rejectAt.UTC().Format(time.RFC3339))
+			if err := repos.Appeals.LogStatusChange(ctx, &model.AppealStatusLog{
+				AppealID:  appeal.ID,
+				OldStatus: &status,
+				NewStatus: status,
+				Note:      note,
+			}); err != nil {
+				return err
+			}
+
+			payload := appealEventPayload(appeal, nil, note, nil)
+			payload.DueAt = &rejectAt
+			if err := appendEvent(ctx, repos, model.AggregateAppeal, appeal.ID, model.EventAppealReminder, payload); err != nil {
+				return err
+			}
+		}
+		reminded = len(appeals)
+		return nil
+	})
+	return reminded, err
+}
+
+// RejectStaleNeedInfo отклоняет апелляции, оставшиеся без ответа спустя grace после
+// напоминания. Отклонение идёт тем же путём, что и Act с REJECT, но от имени системы:
+// нарушение становится FIXED, оба журнала пишутся с changed_by = NULL.
+func (s *AppealService) RejectStaleNeedInfo(ctx context.Context, now time.Time, grace time.Duration, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "AppealService.RejectStaleNeedInfo")
+	defer span.End()
+
+	rejected := 0
+	err := s.uow.Do(ctx, func(repos repository.Repos) error {
+		appeals, err := repos.Appeals.LockNeedInfoForRejection(ctx, now, grace, limit)
+		if err != nil {
+			return err
+		}
+		for i := range appeals {
+			appeal := &appeals[i]
+			violation, err := repos.Violations.GetByID(ctx, model.Scope{Type: model.ScopeCity}, appeal.ViolationID)
+			if err != nil {
+				return err
+			}
+			appeal.Violation = violation
+			if err := s.applyAction(ctx, repos, appeal, AppealActionReject, autoRejectNote, nil, ""); err != nil {
+				return err
+			}
+		}
+		rejected = len(appeals)
+		return nil
+	})
+	if err != nil {
+		return 0, translateRepoError(err)
+	}
+	metrics.AppealTransitions(string(AppealActionReject), "system", rejected)
+	return rejected, nil
+}
@@ -3,16 +3,25 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"violation-service/internal/logger"
+	"violation-service/internal/metrics"
 	"violation-service/internal/model"
+	"violation-service/internal/policy"
 	"violation-service/internal/repository"
+	"violation-service/internal/sla"
 )
 
+const minAppealReasonLength = 10
+
+var errEmptyMessage = InvalidField("message", "must not be empty")
+
 type AttachmentInput struct {
 	FileURL  string
 	FileType model.AttachmentFileType
@@ -22,6 +31,11 @@ type AppealService struct {
 	scopeRepo      *repository.ScopeRepository
 	violationRepo  *repository.ViolationRepository
 	appealRepo     *repository.AppealRepository
+	userRepo       *repository.UserRepository
+	uow            *repository.UnitOfWork
+	typeRepo       *repository.ViolationTypeRepository
+	sla            *sla.Policy
+	access         *policy.Policy
 	maxAttachments int
 }
 
@@ -29,12 +43,22 @@ func NewAppealService(
 	scopeRepo *repository.ScopeRepository,
 	violationRepo *repository.ViolationRepository,
 	appealRepo *repository.AppealRepository,
+	userRepo *repository.UserRepository,
+	uow *repository.UnitOfWork,
+	typeRepo *repository.ViolationTypeRepository,
+	slaPolicy *sla.Policy,
+	accessPolicy *policy.Policy,
 	maxAttachments int,
 ) *AppealService {
 	return &AppealService{
 		scopeRepo:      scopeRepo,
 		violationRepo:  violationRepo,
 		appealRepo:     appealRepo,
+		userRepo:       userRepo,
+		uow:            uow,
+		typeRepo:       typeRepo,
+		sla:            slaPolicy,
+		access:         accessPolicy,
 		maxAttachments: maxAttachments,
 	}
 }
@@ -46,16 +70,27 @@ type AppealListOptions struct {
 	ContractorIDs  []uuid.UUID
 	DateFrom       *time.Time
 	DateTo         *time.Time
+	Overdue        *bool
+	Cursor         string
+	WithTotal      bool
 	Limit          int
 	Offset         int
 }
 
-func (s *AppealService) List(ctx context.Context, principal model.Principal, opts AppealListOptions) ([]model.Appeal, error) {
+func (s *AppealService) List(ctx context.Context, principal model.Principal, opts AppealListOptions) (*model.Page[model.Appeal], error) {
+	ctx, span := startSpan(ctx, "AppealService.List", principal)
+	defer span.End()
+
 	scope, err := s.resolveScope(ctx, principal)
 	if err != nil {
 		return nil, err
 	}
 
+	cursor, err := decodeCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
 	filter := repository.AppealFilter{
 		Scope:          scope,
 		Statuses:       opts.Statuses,
@@ -64,6 +99,9 @@ func (s *AppealService) List(ctx context.Context, principal model.Principal, opt
 		ContractorIDs:  opts.ContractorIDs,
 		DateFrom:       opts.DateFrom,
 		DateTo:         opts.DateTo,
+		Overdue:        opts.Overdue,
+		Cursor:         cursor,
+		WithTotal:      opts.WithTotal,
 		Limit:          opts.Limit,
 		Offset:         opts.Offset,
 	}
@@ -77,14 +115,17 @@ func (s *AppealService) List(ctx context.Context, principal model.Principal, opt
 		filter.ReasonCodes = []model.AppealReasonCode{model.AppealReasonCameraError}
 	}
 
-	appeals, err := s.appealRepo.List(ctx, filter)
+	page, err := s.appealRepo.List(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
-	return appeals, nil
+	return &page, nil
 }
 
 func (s *AppealService) Get(ctx context.Context, principal model.Principal, appealID uuid.UUID) (*model.Appeal, error) {
+	ctx, span := startSpan(ctx, "AppealService.Get", principal)
+	defer span.End()
+
 	scope, err := s.resolveScope(ctx, principal)
 	if err != nil {
 		return nil, err
@@ -99,9 +140,47 @@ func (s *AppealService) Get(ctx context.Context, principal model.Principal, appe
 	return appeal, nil
 }
 
+func (s *AppealService) History(ctx context.Context, principal model.Principal, appealID uuid.UUID) ([]model.AppealStatusLogDTO, error) {
+	ctx, span := startSpan(ctx, "AppealService.History", principal)
+	defer span.End()
+
+	appeal, err := s.Get(ctx, principal, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.appealRepo.ListStatusLog(ctx, []uuid.UUID{appeal.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	actorIDs := make([]uuid.UUID, 0, len(logs))
+	for _, entry := range logs {
+		if entry.ChangedBy != nil {
+			actorIDs = append(actorIDs, *entry.ChangedBy)
+		}
+	}
+	actors, err := s.userRepo.ActorsByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.AppealStatusLogDTO, 0, len(logs))
+	for _, entry := range logs {
+		result = append(result, model.AppealStatusLogDTO{
+			AppealStatusLog: entry,
+			Actor:           lookupActor(actors, entry.ChangedBy, ""),
+		})
+	}
+	return result, nil
+}
+
 func (s *AppealService) Create(ctx context.Context, principal model.Principal, violationID uuid.UUID, reasonCode model.AppealReasonCode, reasonText string, attachments []AttachmentInput) (*model.Appeal, error) {
-	if !(principal.IsDriver() || principal.IsContractor()) {
-		return nil, ErrPermissionDenied
+	ctx, span := startSpan(ctx, "AppealService.Create", principal)
+	defer span.End()
+
+	if err := authorize(s.access, principal, policy.AppealCreate, ""); err != nil {
+		return nil, err
 	}
 
 	scope, err := s.resolveScope(ctx, principal)
@@ -116,6 +195,20 @@ func (s *AppealService) Create(ctx context.Context, principal model.Principal, v
 		}
 		return nil, err
 	}
+	if err := authorize(s.access, principal, policy.AppealCreate, string(violation.Status)); err != nil {
+		return nil, err
+	}
+	definition, err := s.typeRepo.Get(ctx, violation.Type)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Тип удалён из каталога или нарушение создано до его заполнения.
+		err = repository.ErrUnknownViolationType
+	}
+	if err != nil {
+		return nil, translateRepoError(err)
+	}
+	if !definition.Appealable {
+		return nil, InvalidField("type", "violations of type "+string(violation.Type)+" cannot be appealed")
+	}
 
 	if principal.IsDriver() {
 		if violation.Trip == nil || violation.Trip.Driver == nil || principal.DriverID == nil || violation.Trip.Driver.ID != *principal.DriverID {
@@ -133,16 +226,16 @@ func (s *AppealService) Create(ctx context.Context, principal model.Principal, v
 		return nil, err
 	}
 	if activeCount > 0 {
-		return nil, ErrConflict
+		return nil, errActiveAppealExists
 	}
 
 	reasonText = strings.TrimSpace(reasonText)
-	if len(reasonText) < 10 {
-		return nil, ErrInvalidInput
+	if len(reasonText) < minAppealReasonLength {
+		return nil, InvalidField("reason_text", fmt.Sprintf("must be at least %d characters", minAppealReasonLength))
 	}
 
-	if len(attachments) > s.maxAttachments {
-		return nil, ErrInvalidInput
+	if err := s.checkAttachmentCount(attachments); err != nil {
+		return nil, err
 	}
 
 	modelAttachments, err := s.buildAttachments(attachments, principal.UserID)
@@ -156,6 +249,7 @@ func (s *AppealService) Create(ctx context.Context, principal model.Principal, v
 		ReasonCode:   reasonCode,
 		ReasonText:   reasonText,
 		Status:       model.AppealStatusSubmitted,
+		DueAt:        s.sla.DueAt(model.AppealStatusSubmitted, reasonCode, time.Now()),
 		DriverID:     nil,
 		TicketID:     nil,
 		ContractorID: nil,
@@ -180,18 +274,30 @@ func (s *AppealService) Create(ctx context.Context, principal model.Principal, v
 		Message:    reasonText,
 	}
 
-	if err := s.appealRepo.CreateAppeal(ctx, appeal, modelAttachments, comment); err != nil {
-		return nil, err
-	}
+	err = s.uow.Do(ctx, func(repos repository.Repos) error {
+		if err := repos.Appeals.CreateAppeal(ctx, appeal, modelAttachments, comment); err != nil {
+			return err
+		}
+		if err := repos.Appeals.LogStatusChange(ctx, &model.AppealStatusLog{
+			AppealID:  appeal.ID,
+			NewStatus: model.AppealStatusSubmitted,
+			Note:      reasonText,
+			ChangedBy: &principal.UserID,
+		}); err != nil {
+			return err
+		}
 
-	if err := s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-		AppealID:  appeal.ID,
-		NewStatus: model.AppealStatusSubmitted,
-		Note:      reasonText,
-		ChangedBy: &principal.UserID,
-	}); err != nil {
-		return nil, err
+		appeal.Violation = violation
+		return recordAppealEvent(ctx, repos, model.EventAppealCreated, appeal, nil, reasonText, &principal.UserID)
+	})
+	if err != nil {
+		return nil, translateRepoError(err)
 	}
+	logger.FromContext(ctx).Info().
+		Stringer("appeal_id", appeal.ID).
+		Stringer("violation_id", violationID).
+		Str("reason_code", string(reasonCode)).
+		Msg("appeal submitted")
 
 	created, err := s.appealRepo.GetByID(ctx, scope, appeal.ID)
 	if err != nil {
@@ -201,6 +307,9 @@ func (s *AppealService) Create(ctx context.Context, principal model.Principal, v
 }
 
 func (s *AppealService) AddComment(ctx context.Context, principal model.Principal, appealID uuid.UUID, message string, attachments []AttachmentInput) error {
+	ctx, span := startSpan(ctx, "AppealService.AddComment", principal)
+	defer span.End()
+
 	scope, err := s.resolveScope(ctx, principal)
 	if err != nil {
 		return err
@@ -214,17 +323,20 @@ func (s *AppealService) AddComment(ctx context.Context, principal model.Principa
 		return err
 	}
 
-	if !s.canParticipate(principal, appeal) && !principal.IsAkimat() && !principal.IsKgu() && !principal.IsToo() {
+	if err := authorize(s.access, principal, policy.AppealComment, string(appeal.Status)); err != nil {
+		return err
+	}
+	if !s.canParticipate(principal, appeal) {
 		return ErrPermissionDenied
 	}
 
 	message = strings.TrimSpace(message)
 	if message == "" {
-		return ErrInvalidInput
+		return errEmptyMessage
 	}
 
-	if len(attachments) > s.maxAttachments {
-		return ErrInvalidInput
+	if err := s.checkAttachmentCount(attachments); err != nil {
+		return err
 	}
 
 	modelAttachments, err := s.buildAttachments(attachments, principal.UserID)
@@ -239,28 +351,20 @@ func (s *AppealService) AddComment(ctx context.Context, principal model.Principa
 		Message:    message,
 	}
 
-	if err := s.appealRepo.AddComment(ctx, comment, modelAttachments); err != nil {
-		return err
-	}
-
-	if appeal.Status == model.AppealStatusNeedInfo && (principal.IsDriver() || principal.IsContractor()) {
-		oldStatus := appeal.Status
-		if err := s.appealRepo.UpdateStatus(ctx, appeal.ID, model.AppealStatusUnderReview, nil); err != nil {
+	err = s.uow.Do(ctx, func(repos repository.Repos) error {
+		if err := repos.Appeals.AddComment(ctx, comment, modelAttachments); err != nil {
 			return err
 		}
-		appeal.Status = model.AppealStatusUnderReview
-		if err := s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-			AppealID:  appeal.ID,
-			OldStatus: &oldStatus,
-			NewStatus: appeal.Status,
-			Note:      "answer received",
-			ChangedBy: &principal.UserID,
-		}); err != nil {
+		if err := recordAppealEvent(ctx, repos, model.EventAppealCommentAdded, appeal, nil, message, &principal.UserID); err != nil {
 			return err
 		}
-	}
 
-	return nil
+		if appeal.Status == model.AppealStatusNeedInfo && (principal.IsDriver() || principal.IsContractor()) {
+			return s.setAppealStatus(ctx, repos, appeal, model.AppealStatusUnderReview, nil, "answer received", &principal.UserID)
+		}
+		return nil
+	})
+	return translateRepoError(err)
 }
 
 type AppealAction string
@@ -273,9 +377,20 @@ const (
 	AppealActionClose       AppealAction = "CLOSE"
 )
 
-func (s *AppealService) Act(ctx context.Context, principal model.Principal, appealID uuid.UUID, action AppealAction, message string) error {
-	if !(principal.IsAkimat() || principal.IsKgu()) {
-		return ErrPermissionDenied
+var errUnknownAppealAction = InvalidField("action", "unknown action", allowedValues([]AppealAction{
+	AppealActionStartReview, AppealActionNeedInfo, AppealActionApprove, AppealActionReject, AppealActionClose,
+})...)
+
+func (s *AppealService) Act(ctx context.Context, principal model.Principal, appealID uuid.UUID, expectedVersion int64, action AppealAction, message string) error {
+	ctx, span := startSpan(ctx, "AppealService.Act", principal)
+	defer span.End()
+
+	policyAction, ok := appealActionPolicy[action]
+	if !ok {
+		return errUnknownAppealAction
+	}
+	if err := authorize(s.access, principal, policyAction, ""); err != nil {
+		return err
 	}
 
 	scope, err := s.resolveScope(ctx, principal)
@@ -291,124 +406,143 @@ func (s *AppealService) Act(ctx context.Context, principal model.Principal, appe
 		return err
 	}
 
+	if appeal.Version != expectedVersion {
+		return ErrPrecondition
+	}
+
 	switch action {
 	case AppealActionStartReview:
 		if appeal.Status != model.AppealStatusSubmitted && appeal.Status != model.AppealStatusNeedInfo {
 			return ErrInvalidStatus
 		}
-		oldStatus := appeal.Status
-		if err := s.appealRepo.UpdateStatus(ctx, appeal.ID, model.AppealStatusUnderReview, nil); err != nil {
-			return err
-		}
-		appeal.Status = model.AppealStatusUnderReview
-		return s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-			AppealID:  appeal.ID,
-			OldStatus: &oldStatus,
-			NewStatus: appeal.Status,
-			Note:      "taken into review",
-			ChangedBy: &principal.UserID,
-		})
 	case AppealActionNeedInfo:
 		if appeal.Status != model.AppealStatusUnderReview {
 			return ErrInvalidStatus
 		}
-		if strings.TrimSpace(message) == "" {
-			return ErrInvalidInput
-		}
-		oldStatus := appeal.Status
-		if err := s.appealRepo.UpdateStatus(ctx, appeal.ID, model.AppealStatusNeedInfo, nil); err != nil {
-			return err
+		message = strings.TrimSpace(message)
+		if message == "" {
+			return errEmptyMessage
 		}
-		appeal.Status = model.AppealStatusNeedInfo
-		if err := s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-			AppealID:  appeal.ID,
-			OldStatus: &oldStatus,
-			NewStatus: appeal.Status,
-			Note:      "requesting additional info",
-			ChangedBy: &principal.UserID,
-		}); err != nil {
-			return err
-		}
-		return s.AddComment(ctx, principal, appeal.ID, message, nil)
-	case AppealActionApprove:
+	case AppealActionApprove, AppealActionReject:
 		if appeal.Status != model.AppealStatusUnderReview && appeal.Status != model.AppealStatusNeedInfo {
 			return ErrInvalidStatus
 		}
-		oldStatus := appeal.Status
-		if err := s.appealRepo.UpdateStatus(ctx, appeal.ID, model.AppealStatusApproved, &principal.UserID); err != nil {
-			return err
-		}
-		appeal.Status = model.AppealStatusApproved
-		if err := s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-			AppealID:  appeal.ID,
-			OldStatus: &oldStatus,
-			NewStatus: appeal.Status,
-			Note:      "appeal approved",
-			ChangedBy: &principal.UserID,
-		}); err != nil {
-			return err
-		}
-		prevViolationStatus := appeal.Violation.Status
-		if err := s.violationRepo.UpdateStatus(ctx, appeal.ViolationID, model.ViolationStatusCanceled, "canceled via appeal approval"); err != nil {
-			return err
-		}
-		appeal.Violation.Status = model.ViolationStatusCanceled
-		return s.violationRepo.LogStatusChange(ctx, &model.ViolationStatusLog{
-			ViolationID: appeal.ViolationID,
-			OldStatus:   &prevViolationStatus,
-			NewStatus:   model.ViolationStatusCanceled,
-			Note:        "canceled via appeal approval",
-			ChangedBy:   &principal.UserID,
-		})
-	case AppealActionReject:
-		if appeal.Status != model.AppealStatusUnderReview && appeal.Status != model.AppealStatusNeedInfo {
+	case AppealActionClose:
+		if appeal.Status != model.AppealStatusApproved && appeal.Status != model.AppealStatusRejected {
 			return ErrInvalidStatus
 		}
-		oldStatus := appeal.Status
-		if err := s.appealRepo.UpdateStatus(ctx, appeal.ID, model.AppealStatusRejected, &principal.UserID); err != nil {
+	default:
+		return errUnknownAppealAction
+	}
+
+	if err := authorize(s.access, principal, policyAction, string(appeal.Status)); err != nil {
+		return err
+	}
+
+	actor := principal.UserID
+	prev := appeal.Status
+	err = s.uow.Do(ctx, func(repos repository.Repos) error {
+		return s.applyAction(ctx, repos, appeal, action, message, &actor, principal.Role)
+	})
+	if err != nil {
+		return translateRepoError(err)
+	}
+	metrics.AppealTransitions(string(action), "user", 1)
+	logger.FromContext(ctx).Info().
+		Stringer("appeal_id", appeal.ID).
+		Str("action", string(action)).
+		Str("from_status", string(prev)).
+		Str("to_status", string(appeal.Status)).
+		Msg("appeal action applied")
+	return nil
+}
+
+// applyAction выполняет уже проверенное действие над апелляцией в транзакции repos.
+// actor == nil означает системное действие: тогда message у REJECT становится заметкой
+// в журнале, а NEED_INFO недоступен, потому что у комментария должен быть автор.
+func (s *AppealService) applyAction(ctx context.Context, repos repository.Repos, appeal *model.Appeal, action AppealAction, message string, actor *uuid.UUID, role model.UserRole) error {
+	switch action {
+	case AppealActionStartReview:
+		return s.setAppealStatus(ctx, repos, appeal, model.AppealStatusUnderReview, nil, "taken into review", actor)
+	case AppealActionNeedInfo:
+		if actor == nil {
+			return ErrInvalidInput
+		}
+		if err := s.setAppealStatus(ctx, repos, appeal, model.AppealStatusNeedInfo, nil, "requesting additional info", actor); err != nil {
 			return err
 		}
-		appeal.Status = model.AppealStatusRejected
-		if err := s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-			AppealID:  appeal.ID,
-			OldStatus: &oldStatus,
-			NewStatus: appeal.Status,
-			Note:      "appeal rejected",
-			ChangedBy: &principal.UserID,
-		}); err != nil {
+		if err := repos.Appeals.AddComment(ctx, &model.AppealComment{
+			AppealID:   appeal.ID,
+			AuthorID:   *actor,
+			AuthorRole: role,
+			Message:    message,
+		}, nil); err != nil {
 			return err
 		}
-		prevViolationStatus := appeal.Violation.Status
-		if err := s.violationRepo.UpdateStatus(ctx, appeal.ViolationID, model.ViolationStatusFixed, "violation confirmed"); err != nil {
+		return recordAppealEvent(ctx, repos, model.EventAppealCommentAdded, appeal, nil, message, actor)
+	case AppealActionApprove:
+		if err := s.setAppealStatus(ctx, repos, appeal, model.AppealStatusApproved, actor, "appeal approved", actor); err != nil {
 			return err
 		}
-		appeal.Violation.Status = model.ViolationStatusFixed
-		return s.violationRepo.LogStatusChange(ctx, &model.ViolationStatusLog{
-			ViolationID: appeal.ViolationID,
-			OldStatus:   &prevViolationStatus,
-			NewStatus:   model.ViolationStatusFixed,
-			Note:        "violation confirmed via appeal rejection",
-			ChangedBy:   &principal.UserID,
-		})
-	case AppealActionClose:
-		if appeal.Status != model.AppealStatusApproved && appeal.Status != model.AppealStatusRejected {
-			return ErrInvalidStatus
+		return s.setViolationStatus(ctx, repos, appeal, model.ViolationStatusCanceled, "canceled via appeal approval", "canceled via appeal approval", actor)
+	case AppealActionReject:
+		note := "appeal rejected"
+		if actor == nil && message != "" {
+			note = message
 		}
-		oldStatus := appeal.Status
-		if err := s.appealRepo.UpdateStatus(ctx, appeal.ID, model.AppealStatusClosed, &principal.UserID); err != nil {
+		if err := s.setAppealStatus(ctx, repos, appeal, model.AppealStatusRejected, actor, note, actor); err != nil {
 			return err
 		}
-		appeal.Status = model.AppealStatusClosed
-		return s.appealRepo.LogStatusChange(ctx, &model.AppealStatusLog{
-			AppealID:  appeal.ID,
-			OldStatus: &oldStatus,
-			NewStatus: appeal.Status,
-			Note:      "appeal closed",
-			ChangedBy: &principal.UserID,
-		})
+		return s.setViolationStatus(ctx, repos, appeal, model.ViolationStatusFixed, "violation confirmed", "violation confirmed via appeal rejection", actor)
 	default:
-		return ErrInvalidInput
+		return s.setAppealStatus(ctx, repos, appeal, model.AppealStatusClosed, actor, "appeal closed", actor)
+	}
+}
+
+// setAppealStatus меняет статус апелляции и пишет запись в appeal_status_log в рамках транзакции repos.
+func (s *AppealService) setAppealStatus(ctx context.Context, repos repository.Repos, appeal *model.Appeal, status model.AppealStatus, resolvedBy *uuid.UUID, note string, actor *uuid.UUID) error {
+	oldStatus := appeal.Status
+	dueAt := s.sla.DueAt(status, appeal.ReasonCode, time.Now())
+	if err := repos.Appeals.UpdateStatus(ctx, appeal.ID, appeal.Version, status, resolvedBy, dueAt); err != nil {
+		return err
+	}
+	now := time.Now()
+	appeal.Status = status
+	appeal.DueAt = dueAt
+	appeal.OverdueAt = nil
+	appeal.StatusChangedAt = &now
+	appeal.RemindedAt = nil
+	appeal.Version++
+	if err := repos.Appeals.LogStatusChange(ctx, &model.AppealStatusLog{
+		AppealID:  appeal.ID,
+		OldStatus: &oldStatus,
+		NewStatus: status,
+		Note:      note,
+		ChangedBy: actor,
+	}); err != nil {
+		return err
+	}
+	return recordAppealEvent(ctx, repos, model.EventAppealStatusChanged, appeal, &oldStatus, note, actor)
+}
+
+// setViolationStatus синхронизирует статус нарушения с решением по апелляции.
+func (s *AppealService) setViolationStatus(ctx context.Context, repos repository.Repos, appeal *model.Appeal, status model.ViolationStatus, description, note string, actor *uuid.UUID) error {
+	prevStatus := appeal.Violation.Status
+	if err := repos.Violations.UpdateStatus(ctx, appeal.ViolationID, appeal.Violation.Version, status, description); err != nil {
+		return err
 	}
+	appeal.Violation.Status = status
+	appeal.Violation.Version++
+	if err := repos.Violations.LogStatusChange(ctx, &model.ViolationStatusLog{
+		ViolationID: appeal.ViolationID,
+		OldStatus:   &prevStatus,
+		NewStatus:   status,
+		Note:        note,
+		ChangedBy:   actor,
+	}); err != nil {
+		return err
+	}
+	return recordViolationEvent(ctx, repos, model.EventViolationStatusChanged, *appeal.Violation, appeal.Violation.Trip, &prevStatus, note, actor)
 }
 
 func (s *AppealService) resolveScope(ctx context.Context, principal model.Principal) (model.Scope, error) {
@@ -422,6 +556,8 @@ func (s *AppealService) resolveScope(ctx context.Context, principal model.Princi
 	return scope, nil
 }
 
+// canParticipate проверяет, что водитель или подрядчик — сторона апелляции. Остальные роли
+// ограничены областью видимости и политикой доступа.
 func (s *AppealService) canParticipate(principal model.Principal, appeal *model.Appeal) bool {
 	switch {
 	case principal.IsDriver():
@@ -429,15 +565,22 @@ func (s *AppealService) canParticipate(principal model.Principal, appeal *model.
 	case principal.IsContractor():
 		return appeal.ContractorID != nil && *appeal.ContractorID == principal.OrgID
 	default:
-		return false
+		return true
 	}
 }
 
+func (s *AppealService) checkAttachmentCount(attachments []AttachmentInput) error {
+	if len(attachments) > s.maxAttachments {
+		return InvalidField("attachments", fmt.Sprintf("at most %d attachments are allowed", s.maxAttachments))
+	}
+	return nil
+}
+
 func (s *AppealService) buildAttachments(inputs []AttachmentInput, uploader uuid.UUID) ([]model.AppealAttachment, error) {
 	attachments := make([]model.AppealAttachment, 0, len(inputs))
-	for _, att := range inputs {
+	for i, att := range inputs {
 		if strings.TrimSpace(att.FileURL) == "" {
-			return nil, ErrInvalidInput
+			return nil, InvalidField(fmt.Sprintf("attachments.%d.file_url", i), "must not be empty")
 		}
 		attachments = append(attachments, model.AppealAttachment{
 			FileURL:    att.FileURL,
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"violation-service/internal/model"
+	"violation-service/internal/repository"
+)
+
+const (
+	slaBackfillBatchSize = 200
+
+	escalationTarget = "AKIMAT"
+)
+
+// BackfillDueDates проставляет due_at активным апелляциям, у которых его нет
+// (созданы до включения SLA). Точкой отсчёта служит updated_at — последний переход.
+func (s *AppealService) BackfillDueDates(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "AppealService.BackfillDueDates")
+	defer span.End()
+
+	updated := 0
+	var cursor *repository.Cursor
+	for {
+		appeals, err := s.appealRepo.ListMissingDueAt(ctx, cursor, slaBackfillBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		for _, appeal := range appeals {
+			dueAt := s.sla.DueAt(appeal.Status, appeal.ReasonCode, appeal.UpdatedAt)
+			if dueAt == nil {
+				continue
+			}
+			if err := s.appealRepo.SetDueAt(ctx, appeal.ID, *dueAt); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		if len(appeals) < slaBackfillBatchSize {
+			return updated, nil
+		}
+		last := appeals[len(appeals)-1]
+		cursor = &repository.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// FlagOverdue помечает до limit апелляций с истёкшим сроком. В журнал статусов пишется
+// заметка без смены статуса; если срок пропустила проверяющая сторона (КГУ),
+// в outbox уходит событие эскалации в Акимат.
+func (s *AppealService) FlagOverdue(ctx context.Context, now time.Time, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "AppealService.FlagOverdue")
+	defer span.End()
+
+	flagged := 0
+	err := s.uow.Do(ctx, func(repos repository.Repos) error {
+		appeals, err := repos.Appeals.LockOverdue(ctx, now, limit)
+		if err != nil {
+			return err
+		}
+		for i := range appeals {
+			appeal := &appeals[i]
+			if err := repos.Appeals.MarkOverdue(ctx, appeal.ID, now); err != nil {
+				return err
+			}
+			appeal.OverdueAt = &now
+
+			status := appeal.Status
+			note := fmt.Sprintf("SLA deadline missed (due %s)", appeal.DueAt.UTC().Format(time.RFC3339))
+			if err := repos.Appeals.LogStatusChange(ctx, &model.AppealStatusLog{
+				AppealID:  appeal.ID,
+				OldStatus: &status,
+				NewStatus: status,
+				Note:      note,
+			}); err != nil {
+				return err
+			}
+
+			if status.AwaitsReviewer() {
+				payload := appealEventPayload(appeal, nil, note, nil)
+				payload.EscalatedTo = escalationTarget
+				if err := appendEvent(ctx, repos, model.AggregateAppeal, appeal.ID, model.EventAppealEscalated, payload); err != nil {
+					return err
+				}
+			}
+		}
+		flagged = len(appeals)
+		return nil
+	})
+	return flagged, err
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+)
+
+// authorize сверяет действие с политикой доступа. state — статус ресурса или "",
+// если ресурс ещё не загружен.
+func authorize(p *policy.Policy, principal model.Principal, action policy.Action, state string) error {
+	if state == "" {
+		if !p.Permits(principal.Role, action) {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
+	if !p.Allows(principal.Role, action, state) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// appealActionPolicy сопоставляет действие над апелляцией с действием политики.
+var appealActionPolicy = map[AppealAction]policy.Action{
+	AppealActionStartReview: policy.AppealStartReview,
+	AppealActionNeedInfo:    policy.AppealNeedInfo,
+	AppealActionApprove:     policy.AppealApprove,
+	AppealActionReject:      policy.AppealReject,
+	AppealActionClose:       policy.AppealClose,
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+)
+
+func TestAuthorize(t *testing.T) {
+	p, err := policy.Parse([]byte(`
+rules:
+  violation.reopen:
+    - roles: [AKIMAT_ADMIN]
+      states: [CANCELED, FIXED]
+  violation.create:
+    - roles: [AKIMAT_ADMIN]
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		role   model.UserRole
+		action policy.Action
+		state  string
+		denied bool
+	}{
+		{name: "granted without state", role: model.UserRoleAkimatAdmin, action: policy.ViolationCreate},
+		{name: "other role", role: model.UserRoleAkimatUser, action: policy.ViolationCreate, denied: true},
+		{name: "action not in policy", role: model.UserRoleAkimatAdmin, action: policy.ViolationSetStatus, denied: true},
+		{name: "state rule before load", role: model.UserRoleAkimatAdmin, action: policy.ViolationReopen},
+		{name: "allowed state", role: model.UserRoleAkimatAdmin, action: policy.ViolationReopen, state: "FIXED"},
+		{name: "disallowed state", role: model.UserRoleAkimatAdmin, action: policy.ViolationReopen, state: "OPEN", denied: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorize(p, model.Principal{Role: tt.role}, tt.action, tt.state)
+			if tt.denied {
+				if !errors.Is(err, ErrPermissionDenied) {
+					t.Fatalf("authorize error = %v, want ErrPermissionDenied", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("authorize error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestAppealActionPolicyCoversEveryAction(t *testing.T) {
+	for _, action := range []AppealAction{
+		AppealActionStartReview,
+		AppealActionNeedInfo,
+		AppealActionApprove,
+		AppealActionReject,
+		AppealActionClose,
+	} {
+		if _, ok := appealActionPolicy[action]; !ok {
+			t.Errorf("appeal action %s has no policy action", action)
+		}
+	}
+}
@@ -1,11 +1,109 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+
+	"violation-service/internal/repository"
+)
+
+// Error — ошибка, которую API отдаёт клиенту как есть: устойчивый Code для программ,
+// Message для людей и, для ошибок ввода, список неверных полей. Status — HTTP-статус ответа.
+type Error struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Status  int          `json:"-"`
+
+	// kind — базовая ошибка, от которой уточнена эта: по ней работает errors.Is.
+	kind *Error
+}
+
+// FieldError описывает одно неверное поле. Вложенные поля пишутся через точку
+// (attachments.0.file_url), Allowed перечисляет допустимые значения, если их немного.
+type FieldError struct {
+	Field   string   `json:"field"`
+	Message string   `json:"message"`
+	Allowed []string `json:"allowed,omitempty"`
+}
 
 var (
-	ErrPermissionDenied = errors.New("permission denied")
-	ErrNotFound         = errors.New("not found")
-	ErrInvalidInput     = errors.New("invalid input")
-	ErrConflict         = errors.New("conflict")
-	ErrInvalidStatus    = errors.New("invalid status transition")
+	ErrUnauthenticated      = &Error{Code: "unauthenticated", Message: "authentication required", Status: http.StatusUnauthorized}
+	ErrPermissionDenied     = &Error{Code: "permission_denied", Message: "permission denied", Status: http.StatusForbidden}
+	ErrNotFound             = &Error{Code: "not_found", Message: "not found", Status: http.StatusNotFound}
+	ErrInvalidInput         = &Error{Code: "invalid_input", Message: "invalid input", Status: http.StatusBadRequest}
+	ErrConflict             = &Error{Code: "conflict", Message: "conflict", Status: http.StatusConflict}
+	ErrInvalidStatus        = &Error{Code: "invalid_status_transition", Message: "invalid status transition", Status: http.StatusBadRequest}
+	ErrPrecondition         = &Error{Code: "precondition_failed", Message: "resource was modified, reload and retry", Status: http.StatusPreconditionFailed}
+	ErrPreconditionRequired = &Error{Code: "precondition_required", Message: "precondition required", Status: http.StatusPreconditionRequired}
+	ErrInternal             = &Error{Code: "internal", Message: "internal error", Status: http.StatusInternalServerError}
+
+	errActiveAppealExists  = newError(ErrConflict, "active_appeal_exists", "violation already has an active appeal")
+	errViolationTypeExists = newError(ErrConflict, "violation_type_exists", "violation type with this code already exists")
+	errViolationTypeInUse  = newError(ErrConflict, "violation_type_in_use", "violation type is used by violations, deactivate it instead")
 )
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is сопоставляет уточнённую ошибку с базовой: errors.Is(err, ErrConflict) верно
+// и для active_appeal_exists.
+func (e *Error) Is(target error) bool {
+	return e.kind != nil && target == error(e.kind)
+}
+
+// WithMessage возвращает ту же ошибку с другим сообщением; код и статус не меняются.
+func (e *Error) WithMessage(message string) *Error {
+	derived := *e
+	derived.Message = message
+	derived.kind = e.base()
+	return &derived
+}
+
+func (e *Error) base() *Error {
+	if e.kind != nil {
+		return e.kind
+	}
+	return e
+}
+
+func newError(kind *Error, code, message string) *Error {
+	return &Error{Code: code, Message: message, Status: kind.Status, kind: kind}
+}
+
+// InvalidField — ErrInvalidInput с указанием поля; так же его собирают обработчики
+// для параметров пути и запроса.
+func InvalidField(field, message string, allowed ...string) *Error {
+	err := ErrInvalidInput.WithMessage(field + ": " + message)
+	err.Fields = []FieldError{{Field: field, Message: message, Allowed: allowed}}
+	return err
+}
+
+func allowedValues[T ~string](values []T) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, string(value))
+	}
+	return result
+}
+
+// translateRepoError переводит известные ошибки репозиториев в ошибки сервиса.
+func translateRepoError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repository.ErrActiveAppealExists):
+		return errActiveAppealExists
+	case errors.Is(err, repository.ErrVersionConflict):
+		return ErrPrecondition
+	case errors.Is(err, repository.ErrViolationTypeExists):
+		return errViolationTypeExists
+	case errors.Is(err, repository.ErrViolationTypeInUse):
+		return errViolationTypeInUse
+	case errors.Is(err, repository.ErrUnknownViolationType):
+		return InvalidField("type", "unknown or inactive violation type")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"violation-service/internal/repository"
+)
+
+func TestTranslateRepoError(t *testing.T) {
+	unexpected := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantKind *Error
+	}{
+		{name: "active appeal", err: repository.ErrActiveAppealExists, wantCode: "active_appeal_exists", wantKind: ErrConflict},
+		{name: "version conflict", err: repository.ErrVersionConflict, wantCode: "precondition_failed", wantKind: ErrPrecondition},
+		{name: "type exists", err: repository.ErrViolationTypeExists, wantCode: "violation_type_exists", wantKind: ErrConflict},
+		{name: "type in use", err: repository.ErrViolationTypeInUse, wantCode: "violation_type_in_use", wantKind: ErrConflict},
+		{name: "unknown type", err: repository.ErrUnknownViolationType, wantCode: "invalid_input", wantKind: ErrInvalidInput},
+		{name: "wrapped", err: fmt.Errorf("create: %w", repository.ErrUnknownViolationType), wantCode: "invalid_input", wantKind: ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiErr *Error
+			if !errors.As(translateRepoError(tt.err), &apiErr) {
+				t.Fatalf("translateRepoError(%v) is not a *service.Error", tt.err)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", apiErr.Code, tt.wantCode)
+			}
+			if !errors.Is(apiErr, tt.wantKind) {
+				t.Errorf("error %v is not %v", apiErr, tt.wantKind)
+			}
+			if apiErr.Status == http.StatusInternalServerError {
+				t.Errorf("known repository error maps to 500")
+			}
+		})
+	}
+
+	if err := translateRepoError(unexpected); err != unexpected {
+		t.Errorf("translateRepoError(unexpected) = %v, want it unchanged", err)
+	}
+	if err := translateRepoError(nil); err != nil {
+		t.Errorf("translateRepoError(nil) = %v, want nil", err)
+	}
+}
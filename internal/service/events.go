@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"violation-service/internal/model"
+	"violation-service/internal/repository"
+)
+
+// recordViolationEvent пишет событие по нарушению в outbox в рамках транзакции repos.
+// trip может быть nil, если поездка не загружена.
+func recordViolationEvent(ctx context.Context, repos repository.Repos, eventType model.EventType, v model.Violation, trip *model.Trip, oldStatus *model.ViolationStatus, note string, actor *uuid.UUID) error {
+	payload := model.EventPayload{
+		ViolationID:   v.ID,
+		TripID:        v.TripID,
+		ViolationType: v.Type,
+		DetectedBy:    v.DetectedBy,
+		Severity:      v.Severity,
+		Status:        string(v.Status),
+		Note:          note,
+		ActorID:       actor,
+	}
+	if oldStatus != nil {
+		payload.OldStatus = stringPtr(string(*oldStatus))
+	}
+	if trip != nil {
+		payload.DriverID = trip.DriverID
+		payload.PolygonID = trip.PolygonID
+		if trip.Ticket != nil {
+			contractorID := trip.Ticket.ContractorID
+			payload.ContractorID = &contractorID
+		}
+	}
+	return appendEvent(ctx, repos, model.AggregateViolation, v.ID, eventType, payload)
+}
+
+// recordAppealEvent пишет событие по апелляции в outbox в рамках транзакции repos.
+func recordAppealEvent(ctx context.Context, repos repository.Repos, eventType model.EventType, appeal *model.Appeal, oldStatus *model.AppealStatus, note string, actor *uuid.UUID) error {
+	payload := appealEventPayload(appeal, oldStatus, note, actor)
+	return appendEvent(ctx, repos, model.AggregateAppeal, appeal.ID, eventType, payload)
+}
+
+func appealEventPayload(appeal *model.Appeal, oldStatus *model.AppealStatus, note string, actor *uuid.UUID) model.EventPayload {
+	appealID := appeal.ID
+	payload := model.EventPayload{
+		ViolationID:  appeal.ViolationID,
+		AppealID:     &appealID,
+		TripID:       appeal.TripID,
+		ContractorID: appeal.ContractorID,
+		DriverID:     appeal.DriverID,
+		ReasonCode:   appeal.ReasonCode,
+		Status:       string(appeal.Status),
+		Note:         note,
+		ActorID:      actor,
+		DueAt:        appeal.DueAt,
+	}
+	if oldStatus != nil {
+		payload.OldStatus = stringPtr(string(*oldStatus))
+	}
+	if appeal.Violation != nil {
+		payload.ViolationType = appeal.Violation.Type
+		payload.DetectedBy = appeal.Violation.DetectedBy
+		payload.Severity = appeal.Violation.Severity
+		if appeal.Violation.Trip != nil {
+			payload.PolygonID = appeal.Violation.Trip.PolygonID
+		}
+	}
+	return payload
+}
+
+func appendEvent(ctx context.Context, repos repository.Repos, aggregateType string, aggregateID uuid.UUID, eventType model.EventType, payload model.EventPayload) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return repos.Outbox.Append(ctx, &model.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       raw,
+	})
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+)
+
+// maxNeedInfoWindowHours ограничивает окно 90 днями, чтобы опечатка не «выключала» автоотклонение.
+const maxNeedInfoWindowHours = 90 * 24
+
+type NeedInfoPolicyService struct {
+	scopeRepo     *repository.ScopeRepository
+	policyRepo    *repository.NeedInfoPolicyRepository
+	defaultWindow time.Duration
+	access        *policy.Policy
+}
+
+func NewNeedInfoPolicyService(scopeRepo *repository.ScopeRepository, policyRepo *repository.NeedInfoPolicyRepository, defaultWindow time.Duration, accessPolicy *policy.Policy) *NeedInfoPolicyService {
+	return &NeedInfoPolicyService{
+		scopeRepo:     scopeRepo,
+		policyRepo:    policyRepo,
+		defaultWindow: defaultWindow,
+		access:        accessPolicy,
+	}
+}
+
+// List показывает Акимату все политики, КГУ — только по своим подрядчикам.
+func (s *NeedInfoPolicyService) List(ctx context.Context, principal model.Principal) (*model.NeedInfoPolicies, error) {
+	orgIDs, err := s.manageableOrgs(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := s.policyRepo.List(ctx, orgIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NeedInfoPolicies{
+		DefaultWindowHours: s.defaultWindow.Hours(),
+		Policies:           policies,
+	}, nil
+}
+
+func (s *NeedInfoPolicyService) Put(ctx context.Context, principal model.Principal, orgID uuid.UUID, windowHours int) (*model.NeedInfoPolicy, error) {
+	if err := s.ensureCanManage(ctx, principal, orgID); err != nil {
+		return nil, err
+	}
+	if windowHours <= 0 || windowHours > maxNeedInfoWindowHours {
+		return nil, InvalidField("window_hours", fmt.Sprintf("must be between 1 and %d", maxNeedInfoWindowHours))
+	}
+
+	policy := &model.NeedInfoPolicy{
+		OrganizationID: orgID,
+		WindowHours:    windowHours,
+		UpdatedBy:      &principal.UserID,
+	}
+	if err := s.policyRepo.Upsert(ctx, policy); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s.policyRepo.Get(ctx, orgID)
+}
+
+// Delete возвращает организацию к окну по умолчанию.
+func (s *NeedInfoPolicyService) Delete(ctx context.Context, principal model.Principal, orgID uuid.UUID) error {
+	if err := s.ensureCanManage(ctx, principal, orgID); err != nil {
+		return err
+	}
+	if err := s.policyRepo.Delete(ctx, orgID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// manageableOrgs возвращает nil для Акимата (все организации) и список подрядчиков для КГУ.
+// Кто вообще может управлять политиками, решает политика доступа.
+func (s *NeedInfoPolicyService) manageableOrgs(ctx context.Context, principal model.Principal) ([]uuid.UUID, error) {
+	if err := authorize(s.access, principal, policy.NeedInfoPolicyManage, ""); err != nil {
+		return nil, err
+	}
+	scope, err := s.scopeRepo.ResolveScope(ctx, principal)
+	if err != nil {
+		if errors.Is(err, repository.ErrScopeUnsupported) {
+			return nil, ErrPermissionDenied
+		}
+		return nil, err
+	}
+	switch scope.Type {
+	case model.ScopeCity:
+		return nil, nil
+	case model.ScopeKgu:
+		if scope.ContractorIDs == nil {
+			return []uuid.UUID{}, nil
+		}
+		return scope.ContractorIDs, nil
+	default:
+		return nil, ErrPermissionDenied
+	}
+}
+
+func (s *NeedInfoPolicyService) ensureCanManage(ctx context.Context, principal model.Principal, orgID uuid.UUID) error {
+	orgIDs, err := s.manageableOrgs(ctx, principal)
+	if err != nil {
+		return err
+	}
+	if orgIDs == nil {
+		return nil
+	}
+	for _, id := range orgIDs {
+		if id == orgID {
+			return nil
+		}
+	}
+	return ErrPermissionDenied
+}
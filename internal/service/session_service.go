@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+)
+
+// SessionCache сбрасывает закэшированные ответы после отзыва, чтобы он сразу действовал
+// на этом экземпляре сервиса. Кэши других реплик не очищаются: там отозванный токен
+// принимается, пока не истечёт SESSION_REVOCATION_CACHE_TTL.
+type SessionCache interface {
+	ForgetSession(sessionID uuid.UUID)
+	ForgetUser(userID uuid.UUID)
+}
+
+type SessionService struct {
+	scopeRepo   *repository.ScopeRepository
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	cache       SessionCache
+	access      *policy.Policy
+}
+
+func NewSessionService(scopeRepo *repository.ScopeRepository, userRepo *repository.UserRepository, sessionRepo *repository.SessionRepository, cache SessionCache, accessPolicy *policy.Policy) *SessionService {
+	return &SessionService{
+		scopeRepo:   scopeRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		cache:       cache,
+		access:      accessPolicy,
+	}
+}
+
+// RevokeSession отзывает одну сессию. Владелец sid неизвестен сервису, поэтому
+// это доступно только ролям с городской областью видимости.
+func (s *SessionService) RevokeSession(ctx context.Context, principal model.Principal, sessionID uuid.UUID, reason string) (*model.RevokedSession, error) {
+	if err := authorize(s.access, principal, policy.SessionRevoke, ""); err != nil {
+		return nil, err
+	}
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	if scope.Type != model.ScopeCity {
+		return nil, ErrPermissionDenied
+	}
+	if sessionID == uuid.Nil {
+		return nil, InvalidField("session_id", "must not be empty")
+	}
+
+	revocation := &model.RevokedSession{
+		SessionID: sessionID,
+		Reason:    strings.TrimSpace(reason),
+		RevokedBy: &principal.UserID,
+	}
+	if err := s.sessionRepo.RevokeSession(ctx, revocation); err != nil {
+		return nil, err
+	}
+	s.cache.ForgetSession(sessionID)
+	return revocation, nil
+}
+
+// RevokeUser отзывает все токены пользователя, выданные до текущего момента.
+// Вне городской области (администраторы КГУ и подрядчиков) пользователь должен
+// принадлежать организации из области.
+func (s *SessionService) RevokeUser(ctx context.Context, principal model.Principal, userID uuid.UUID, reason string) (*model.UserSessionRevocation, error) {
+	if err := authorize(s.access, principal, policy.SessionRevoke, ""); err != nil {
+		return nil, err
+	}
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+	if userID == uuid.Nil {
+		return nil, InvalidField("user_id", "must not be empty")
+	}
+
+	orgID, err := s.userRepo.OrganizationOf(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if scope.Type != model.ScopeCity && (orgID == nil || !slices.Contains(scope.OrganizationIDs, *orgID)) {
+		return nil, ErrPermissionDenied
+	}
+
+	revocation := &model.UserSessionRevocation{
+		UserID:        userID,
+		RevokedBefore: time.Now().UTC(),
+		Reason:        strings.TrimSpace(reason),
+		RevokedBy:     &principal.UserID,
+	}
+	if err := s.sessionRepo.RevokeUser(ctx, revocation); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	s.cache.ForgetUser(userID)
+	return revocation, nil
+}
+
+func (s *SessionService) resolveScope(ctx context.Context, principal model.Principal) (model.Scope, error) {
+	scope, err := s.scopeRepo.ResolveScope(ctx, principal)
+	if err != nil {
+		if errors.Is(err, repository.ErrScopeUnsupported) {
+			return model.Scope{}, ErrPermissionDenied
+		}
+		return model.Scope{}, err
+	}
+	return scope, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"violation-service/internal/model"
+	"violation-service/internal/tracing"
+)
+
+var tracer = otel.Tracer("violation-service/internal/service")
+
+// startSpan открывает спан метода сервиса с атрибутами вызывающего; запросы GORM
+// внутри метода становятся его дочерними спанами.
+func startSpan(ctx context.Context, name string, principal model.Principal) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(tracing.PrincipalAttributes(principal)...))
+}
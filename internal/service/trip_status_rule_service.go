@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+)
+
+const (
+	defaultRuleLogLimit = 100
+	maxRuleLogLimit     = 500
+)
+
+var tripStatusPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,63}$`)
+
+type TripStatusRuleService struct {
+	ruleRepo *repository.TripStatusRuleRepository
+	typeRepo *repository.ViolationTypeRepository
+	userRepo *repository.UserRepository
+	access   *policy.Policy
+}
+
+func NewTripStatusRuleService(ruleRepo *repository.TripStatusRuleRepository, typeRepo *repository.ViolationTypeRepository, userRepo *repository.UserRepository, accessPolicy *policy.Policy) *TripStatusRuleService {
+	return &TripStatusRuleService{
+		ruleRepo: ruleRepo,
+		typeRepo: typeRepo,
+		userRepo: userRepo,
+		access:   accessPolicy,
+	}
+}
+
+type TripStatusRuleInput struct {
+	ViolationType model.ViolationType
+	DetectedBy    model.ViolationDetectedBy
+	Severity      model.ViolationSeverity
+	Enabled       *bool
+}
+
+func (s *TripStatusRuleService) List(ctx context.Context, principal model.Principal) ([]model.TripStatusRule, error) {
+	if err := authorize(s.access, principal, policy.TripStatusRuleManage, ""); err != nil {
+		return nil, err
+	}
+	return s.ruleRepo.List(ctx)
+}
+
+// Put создаёт или заменяет правило статуса. Тип и источник проверяются по справочнику так же,
+// как при ручном создании, чтобы триггер не создавал нарушений, которые нельзя создать руками.
+func (s *TripStatusRuleService) Put(ctx context.Context, principal model.Principal, tripStatus string, input TripStatusRuleInput) (*model.TripStatusRule, error) {
+	if err := authorize(s.access, principal, policy.TripStatusRuleManage, ""); err != nil {
+		return nil, err
+	}
+	if err := validateTripStatus(tripStatus); err != nil {
+		return nil, err
+	}
+
+	manual := CreateViolationInput{Type: input.ViolationType, DetectedBy: input.DetectedBy, Severity: input.Severity}
+	if err := validateManualViolation(ctx, s.typeRepo, &manual); err != nil {
+		return nil, err
+	}
+
+	rule := &model.TripStatusRule{
+		TripStatus:    tripStatus,
+		ViolationType: manual.Type,
+		DetectedBy:    manual.DetectedBy,
+		Severity:      manual.Severity,
+		Enabled:       input.Enabled == nil || *input.Enabled,
+		UpdatedBy:     &principal.UserID,
+	}
+	if err := s.ruleRepo.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Delete убирает собственное правило статуса: дальше к нему применяется правило '*'.
+// Удаление '*' отключает автосоздание для всех статусов без правила.
+func (s *TripStatusRuleService) Delete(ctx context.Context, principal model.Principal, tripStatus string) error {
+	if err := authorize(s.access, principal, policy.TripStatusRuleManage, ""); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(ctx, tripStatus, principal.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *TripStatusRuleService) History(ctx context.Context, principal model.Principal, tripStatus string, limit int) ([]model.TripStatusRuleLogDTO, error) {
+	if err := authorize(s.access, principal, policy.TripStatusRuleManage, ""); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultRuleLogLimit
+	}
+	if limit > maxRuleLogLimit {
+		limit = maxRuleLogLimit
+	}
+
+	entries, err := s.ruleRepo.ListLog(ctx, tripStatus, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ChangedBy != nil && !slices.Contains(ids, *entry.ChangedBy) {
+			ids = append(ids, *entry.ChangedBy)
+		}
+	}
+	actors, err := s.userRepo.ActorsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.TripStatusRuleLogDTO, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, model.TripStatusRuleLogDTO{
+			TripStatusRuleLog: entry,
+			Actor:             lookupActor(actors, entry.ChangedBy, ""),
+		})
+	}
+	return result, nil
+}
+
+// Preview показывает, что триггер сделает при переходе рейса в tripStatus. Результат
+// считает та же SQL-функция, что и триггеры, поэтому он не может разойтись с ними.
+func (s *TripStatusRuleService) Preview(ctx context.Context, principal model.Principal, tripStatus string) (*model.TripStatusPreview, error) {
+	if err := authorize(s.access, principal, policy.TripStatusRuleManage, ""); err != nil {
+		return nil, err
+	}
+	if err := validateTripStatus(tripStatus); err != nil {
+		return nil, err
+	}
+
+	preview := &model.TripStatusPreview{TripStatus: tripStatus}
+	matched, err := s.ruleRepo.Find(ctx, tripStatus)
+	if err != nil {
+		return nil, err
+	}
+	if matched == nil {
+		if matched, err = s.ruleRepo.Find(ctx, model.DefaultTripStatusRule); err != nil {
+			return nil, err
+		}
+	}
+	preview.MatchedRule = matched
+
+	violation, err := s.ruleRepo.Evaluate(ctx, tripStatus)
+	if err != nil {
+		return nil, err
+	}
+	if violation != nil {
+		reason := "Auto violation: " + tripStatus
+		preview.CreatesViolation = true
+		preview.Violation = violation
+		preview.ViolationReason = &reason
+	}
+	return preview, nil
+}
+
+// validateTripStatus допускает код статуса или '*'; OK триггеры не обрабатывают.
+func validateTripStatus(tripStatus string) error {
+	if tripStatus == model.DefaultTripStatusRule {
+		return nil
+	}
+	if tripStatus == "OK" || !tripStatusPattern.MatchString(tripStatus) {
+		return InvalidField("trip_status", "must be an upper-case trip status other than OK, or *")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/logger"
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+)
+
+// ReopenAppealMode определяет судьбу последней апелляции при переоткрытии нарушения.
+type ReopenAppealMode string
+
+const (
+	// ReopenAppealSupersede закрывает решённую апелляцию: прежнее решение больше не действует,
+	// и водитель или подрядчик может подать новую апелляцию.
+	ReopenAppealSupersede ReopenAppealMode = "SUPERSEDE"
+	// ReopenAppealReopen возвращает апелляцию в UNDER_REVIEW; новая апелляция невозможна,
+	// пока эта не решена повторно.
+	ReopenAppealReopen ReopenAppealMode = "REOPEN"
+)
+
+const minReopenReasonLength = 10
+
+// ReopenViolation возвращает CANCELED/FIXED нарушение в OPEN с обязательным обоснованием.
+// Метод живёт в AppealService, потому что вместе с нарушением меняет и апелляцию
+// (дедлайн SLA, журнал, события).
+func (s *AppealService) ReopenViolation(ctx context.Context, principal model.Principal, violationID uuid.UUID, expectedVersion int64, reason string, mode ReopenAppealMode) error {
+	ctx, span := startSpan(ctx, "AppealService.ReopenViolation", principal)
+	defer span.End()
+
+	if err := authorize(s.access, principal, policy.ViolationReopen, ""); err != nil {
+		return err
+	}
+
+	reason = strings.TrimSpace(reason)
+	if len([]rune(reason)) < minReopenReasonLength {
+		return InvalidField("reason", fmt.Sprintf("must be at least %d characters", minReopenReasonLength))
+	}
+	if mode == "" {
+		mode = ReopenAppealSupersede
+	}
+	if mode != ReopenAppealSupersede && mode != ReopenAppealReopen {
+		return InvalidField("appeal", "unknown mode", string(ReopenAppealSupersede), string(ReopenAppealReopen))
+	}
+
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return err
+	}
+
+	violation, err := s.violationRepo.GetByID(ctx, scope, violationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+
+	if violation.Version != expectedVersion {
+		return ErrPrecondition
+	}
+
+	if violation.Status != model.ViolationStatusCanceled && violation.Status != model.ViolationStatusFixed {
+		return ErrInvalidStatus
+	}
+
+	if err := authorize(s.access, principal, policy.ViolationReopen, string(violation.Status)); err != nil {
+		return err
+	}
+
+	appeals, err := s.appealRepo.ListByViolationID(ctx, scope, violation.ID)
+	if err != nil {
+		return err
+	}
+	var last *model.Appeal
+	if len(appeals) > 0 {
+		last = &appeals[len(appeals)-1]
+		last.Violation = violation
+	}
+
+	actor := principal.UserID
+	note := "reopened: " + reason
+	prev := violation.Status
+	err = s.uow.Do(ctx, func(repos repository.Repos) error {
+		if err := repos.Violations.Reopen(ctx, violation.ID, violation.Version); err != nil {
+			return err
+		}
+		violation.Status = model.ViolationStatusOpen
+		violation.Version++
+		if err := repos.Violations.LogStatusChange(ctx, &model.ViolationStatusLog{
+			ViolationID: violation.ID,
+			OldStatus:   &prev,
+			NewStatus:   model.ViolationStatusOpen,
+			Note:        note,
+			ChangedBy:   &actor,
+		}); err != nil {
+			return err
+		}
+		if err := recordViolationEvent(ctx, repos, model.EventViolationStatusChanged, *violation, violation.Trip, &prev, note, &actor); err != nil {
+			return err
+		}
+
+		// Активную апелляцию (возможна после ручной смены статуса) не трогаем.
+		if last == nil || last.Status.IsActive() {
+			return nil
+		}
+		switch mode {
+		case ReopenAppealReopen:
+			return s.setAppealStatus(ctx, repos, last, model.AppealStatusUnderReview, nil, note, &actor)
+		default:
+			if last.Status == model.AppealStatusClosed {
+				return nil
+			}
+			return s.setAppealStatus(ctx, repos, last, model.AppealStatusClosed, &actor, "superseded by violation reopen: "+reason, &actor)
+		}
+	})
+	if err != nil {
+		return translateRepoError(err)
+	}
+	logger.FromContext(ctx).Info().
+		Stringer("violation_id", violation.ID).
+		Str("from_status", string(prev)).
+		Str("appeal_mode", string(mode)).
+		Msg("violation reopened")
+	return nil
+}
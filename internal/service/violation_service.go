@@ -3,13 +3,17 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"violation-service/internal/logger"
+	"violation-service/internal/metrics"
 	"violation-service/internal/model"
+	"violation-service/internal/policy"
 	"violation-service/internal/repository"
 )
 
@@ -17,17 +21,29 @@ type ViolationService struct {
 	scopeRepo     *repository.ScopeRepository
 	violationRepo *repository.ViolationRepository
 	appealRepo    *repository.AppealRepository
+	userRepo      *repository.UserRepository
+	uow           *repository.UnitOfWork
+	typeRepo      *repository.ViolationTypeRepository
+	access        *policy.Policy
 }
 
 func NewViolationService(
 	scopeRepo *repository.ScopeRepository,
 	violationRepo *repository.ViolationRepository,
 	appealRepo *repository.AppealRepository,
+	userRepo *repository.UserRepository,
+	uow *repository.UnitOfWork,
+	typeRepo *repository.ViolationTypeRepository,
+	accessPolicy *policy.Policy,
 ) *ViolationService {
 	return &ViolationService{
 		scopeRepo:     scopeRepo,
 		violationRepo: violationRepo,
 		appealRepo:    appealRepo,
+		userRepo:      userRepo,
+		uow:           uow,
+		typeRepo:      typeRepo,
+		access:        accessPolicy,
 	}
 }
 
@@ -43,6 +59,8 @@ type ListViolationsOptions struct {
 	DateFrom       *time.Time
 	DateTo         *time.Time
 	Search         string
+	Cursor         string
+	WithTotal      bool
 	Limit          int
 	Offset         int
 }
@@ -52,12 +70,124 @@ type ViolationDetails struct {
 	Appeals []model.Appeal        `json:"appeals"`
 }
 
-func (s *ViolationService) List(ctx context.Context, principal model.Principal, opts ListViolationsOptions) ([]model.ViolationRecord, error) {
+func (s *ViolationService) List(ctx context.Context, principal model.Principal, opts ListViolationsOptions) (*model.Page[model.ViolationRecord], error) {
+	ctx, span := startSpan(ctx, "ViolationService.List", principal)
+	defer span.End()
+
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := decodeCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := buildViolationFilter(scope, opts)
+	filter.Cursor = cursor
+	filter.WithTotal = opts.WithTotal
+	filter.Limit = opts.Limit
+	filter.Offset = opts.Offset
+
+	page, err := s.violationRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.buildRecords(ctx, page.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Page[model.ViolationRecord]{
+		Items:      records,
+		NextCursor: page.NextCursor,
+		HasMore:    page.HasMore,
+		Total:      page.Total,
+	}, nil
+}
+
+// Stats агрегирует нарушения по выбранным измерениям. Пагинация из opts игнорируется.
+func (s *ViolationService) Stats(ctx context.Context, principal model.Principal, opts ListViolationsOptions, groupBy []model.StatsDimension) (*model.ViolationStats, error) {
+	ctx, span := startSpan(ctx, "ViolationService.Stats", principal)
+	defer span.End()
+
+	dims, err := normalizeStatsDimensions(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.violationRepo.Stats(ctx, buildViolationFilter(scope, opts), dims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ViolationStats{GroupBy: dims, Groups: rows}, nil
+}
+
+// normalizeStatsDimensions убирает повторы и допускает не больше одной временной гранулярности.
+func normalizeStatsDimensions(groupBy []model.StatsDimension) ([]model.StatsDimension, error) {
+	dims := make([]model.StatsDimension, 0, len(groupBy))
+	seen := make(map[model.StatsDimension]bool, len(groupBy))
+	hasPeriod := false
+	for _, dim := range groupBy {
+		if !model.IsKnownStatsDimension(dim) {
+			return nil, InvalidField("group_by", "unknown dimension "+string(dim))
+		}
+		if seen[dim] {
+			continue
+		}
+		if dim.IsPeriod() {
+			if hasPeriod {
+				return nil, InvalidField("group_by", "at most one of day, week or month")
+			}
+			hasPeriod = true
+		}
+		seen[dim] = true
+		dims = append(dims, dim)
+	}
+	return dims, nil
+}
+
+const exportBatchSize = 500
+
+// ViolationExport — подготовленная выгрузка: область и фильтры уже проверены,
+// строки читаются только в Each.
+type ViolationExport struct {
+	service *ViolationService
+	filter  repository.ViolationFilter
+}
+
+// PrepareExport проверяет доступ и фильтры до того, как начнётся запись ответа.
+// Пагинация из opts игнорируется: выгружаются все подходящие записи.
+func (s *ViolationService) PrepareExport(ctx context.Context, principal model.Principal, opts ListViolationsOptions) (*ViolationExport, error) {
+	ctx, span := startSpan(ctx, "ViolationService.PrepareExport", principal)
+	defer span.End()
+
 	scope, err := s.resolveScope(ctx, principal)
 	if err != nil {
 		return nil, err
 	}
+	return &ViolationExport{service: s, filter: buildViolationFilter(scope, opts)}, nil
+}
 
+func (e *ViolationExport) Each(ctx context.Context, fn func(records []model.ViolationRecord) error) error {
+	return e.service.violationRepo.Stream(ctx, e.filter, exportBatchSize, func(batch []model.Violation) error {
+		records, err := e.service.buildRecords(ctx, batch)
+		if err != nil {
+			return err
+		}
+		return fn(records)
+	})
+}
+
+func buildViolationFilter(scope model.Scope, opts ListViolationsOptions) repository.ViolationFilter {
 	filter := repository.ViolationFilter{
 		Scope:          scope,
 		Statuses:       opts.Statuses,
@@ -71,19 +201,19 @@ func (s *ViolationService) List(ctx context.Context, principal model.Principal,
 		DateFrom:       opts.DateFrom,
 		DateTo:         opts.DateTo,
 		Search:         opts.Search,
-		Limit:          opts.Limit,
-		Offset:         opts.Offset,
 	}
 
 	if scope.Type == model.ScopeDriver && scope.DriverID != nil {
 		filter.DriverID = scope.DriverID
 	}
 
-	violations, err := s.violationRepo.List(ctx, filter)
-	if err != nil {
-		return nil, err
-	}
+	// Technical users only see violations that already carry a camera appeal.
+	filter.RequireCameraAppeal = scope.Type == model.ScopeTechnical
 
+	return filter
+}
+
+func (s *ViolationService) buildRecords(ctx context.Context, violations []model.Violation) ([]model.ViolationRecord, error) {
 	ids := make([]uuid.UUID, 0, len(violations))
 	for _, v := range violations {
 		ids = append(ids, v.ID)
@@ -94,22 +224,17 @@ func (s *ViolationService) List(ctx context.Context, principal model.Principal,
 		return nil, err
 	}
 
-	requireCameraAppeal := scope.Type == model.ScopeTechnical
-
 	records := make([]model.ViolationRecord, 0, len(violations))
 	for _, v := range violations {
-		summary := summaries[v.ID]
-		if requireCameraAppeal && !summary.HasCamera {
-			continue
-		}
-		record := buildViolationRecord(v, summary)
-		records = append(records, record)
+		records = append(records, buildViolationRecord(v, summaries[v.ID]))
 	}
-
 	return records, nil
 }
 
 func (s *ViolationService) GetDetails(ctx context.Context, principal model.Principal, violationID uuid.UUID) (*ViolationDetails, error) {
+	ctx, span := startSpan(ctx, "ViolationService.GetDetails", principal)
+	defer span.End()
+
 	scope, err := s.resolveScope(ctx, principal)
 	if err != nil {
 		return nil, err
@@ -141,6 +266,182 @@ func (s *ViolationService) GetDetails(ctx context.Context, principal model.Princ
 	}, nil
 }
 
+func (s *ViolationService) History(ctx context.Context, principal model.Principal, violationID uuid.UUID) ([]model.ViolationStatusLogDTO, error) {
+	ctx, span := startSpan(ctx, "ViolationService.History", principal)
+	defer span.End()
+
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	violation, err := s.violationRepo.GetByID(ctx, scope, violationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	logs, err := s.violationRepo.ListStatusLog(ctx, violation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	actorIDs := make([]uuid.UUID, 0, len(logs))
+	for _, entry := range logs {
+		if entry.ChangedBy != nil {
+			actorIDs = append(actorIDs, *entry.ChangedBy)
+		}
+	}
+	actors, err := s.userRepo.ActorsByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ViolationStatusLogDTO, 0, len(logs))
+	for _, entry := range logs {
+		result = append(result, model.ViolationStatusLogDTO{
+			ViolationStatusLog: entry,
+			Actor:              lookupActor(actors, entry.ChangedBy, ""),
+		})
+	}
+	return result, nil
+}
+
+// Timeline объединяет смены статусов нарушения и апелляций, подачу апелляций,
+// комментарии и вложения в одну хронологию.
+func (s *ViolationService) Timeline(ctx context.Context, principal model.Principal, violationID uuid.UUID) ([]model.TimelineEvent, error) {
+	ctx, span := startSpan(ctx, "ViolationService.Timeline", principal)
+	defer span.End()
+
+	scope, err := s.resolveScope(ctx, principal)
+	if err != nil {
+		return nil, err
+	}
+
+	violation, err := s.violationRepo.GetByID(ctx, scope, violationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	violationLogs, err := s.violationRepo.ListStatusLog(ctx, violation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	appeals, err := s.appealRepo.ListByViolationID(ctx, scope, violation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	appealIDs := make([]uuid.UUID, 0, len(appeals))
+	for _, appeal := range appeals {
+		appealIDs = append(appealIDs, appeal.ID)
+	}
+	appealLogs, err := s.appealRepo.ListStatusLog(ctx, appealIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	actorIDs := make([]uuid.UUID, 0)
+	for _, entry := range violationLogs {
+		if entry.ChangedBy != nil {
+			actorIDs = append(actorIDs, *entry.ChangedBy)
+		}
+	}
+	for _, entry := range appealLogs {
+		if entry.ChangedBy != nil {
+			actorIDs = append(actorIDs, *entry.ChangedBy)
+		}
+	}
+	for _, appeal := range appeals {
+		for _, comment := range appeal.Comments {
+			actorIDs = append(actorIDs, comment.AuthorID)
+		}
+		for _, att := range appeal.Attachments {
+			actorIDs = append(actorIDs, att.UploadedBy)
+		}
+	}
+	actors, err := s.userRepo.ActorsByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reasonByAppeal := make(map[uuid.UUID]model.AppealReasonCode, len(appeals))
+	for _, appeal := range appeals {
+		reasonByAppeal[appeal.ID] = appeal.ReasonCode
+	}
+
+	events := make([]model.TimelineEvent, 0)
+	for _, entry := range violationLogs {
+		event := model.TimelineEvent{
+			Type:      model.TimelineViolationStatus,
+			At:        entry.CreatedAt,
+			Actor:     lookupActor(actors, entry.ChangedBy, ""),
+			NewStatus: stringPtr(string(entry.NewStatus)),
+			Note:      entry.Note,
+		}
+		if entry.OldStatus != nil {
+			event.OldStatus = stringPtr(string(*entry.OldStatus))
+		}
+		events = append(events, event)
+	}
+	for _, entry := range appealLogs {
+		appealID := entry.AppealID
+		event := model.TimelineEvent{
+			Type:      model.TimelineAppealStatus,
+			At:        entry.CreatedAt,
+			AppealID:  &appealID,
+			Actor:     lookupActor(actors, entry.ChangedBy, ""),
+			NewStatus: stringPtr(string(entry.NewStatus)),
+			Note:      entry.Note,
+		}
+		if entry.OldStatus != nil {
+			event.OldStatus = stringPtr(string(*entry.OldStatus))
+		} else {
+			// Первая запись журнала апелляции — это её подача.
+			reason := reasonByAppeal[appealID]
+			event.Type = model.TimelineAppealSubmitted
+			event.ReasonCode = &reason
+		}
+		events = append(events, event)
+	}
+	for _, appeal := range appeals {
+		appealID := appeal.ID
+		for _, comment := range appeal.Comments {
+			authorID := comment.AuthorID
+			events = append(events, model.TimelineEvent{
+				Type:     model.TimelineAppealComment,
+				At:       comment.CreatedAt,
+				AppealID: &appealID,
+				Actor:    lookupActor(actors, &authorID, comment.AuthorRole),
+				Note:     comment.Message,
+			})
+		}
+		for _, att := range appeal.Attachments {
+			uploader := att.UploadedBy
+			events = append(events, model.TimelineEvent{
+				Type:     model.TimelineAttachment,
+				At:       att.CreatedAt,
+				AppealID: &appealID,
+				Actor:    lookupActor(actors, &uploader, ""),
+				FileURL:  att.FileURL,
+				FileType: att.FileType,
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At)
+	})
+
+	return events, nil
+}
+
 type CreateViolationInput struct {
 	TripID      uuid.UUID
 	Type        model.ViolationType
@@ -150,8 +451,14 @@ type CreateViolationInput struct {
 }
 
 func (s *ViolationService) CreateManual(ctx context.Context, principal model.Principal, input CreateViolationInput) (*model.ViolationRecord, error) {
-	if !(principal.IsAkimat() || principal.IsKgu()) {
-		return nil, ErrPermissionDenied
+	ctx, span := startSpan(ctx, "ViolationService.CreateManual", principal)
+	defer span.End()
+
+	if err := authorize(s.access, principal, policy.ViolationCreate, ""); err != nil {
+		return nil, err
+	}
+	if err := validateManualViolation(ctx, s.typeRepo, &input); err != nil {
+		return nil, err
 	}
 
 	scope, err := s.resolveScope(ctx, principal)
@@ -185,24 +492,37 @@ func (s *ViolationService) CreateManual(ctx context.Context, principal model.Pri
 		Description: input.Description,
 	}
 
-	if err := s.violationRepo.Create(ctx, violation); err != nil {
-		return nil, err
-	}
+	err = s.uow.Do(ctx, func(repos repository.Repos) error {
+		if err := repos.Violations.Create(ctx, violation); err != nil {
+			return err
+		}
 
-	if strings.TrimSpace(input.Description) != "" {
-		if err := s.violationRepo.UpdateTripViolationReason(ctx, trip.ID, input.Description); err != nil {
-			return nil, err
+		if strings.TrimSpace(input.Description) != "" {
+			if err := repos.Violations.UpdateTripViolationReason(ctx, trip.ID, input.Description); err != nil {
+				return err
+			}
 		}
-	}
 
-	if err := s.violationRepo.LogStatusChange(ctx, &model.ViolationStatusLog{
-		ViolationID: violation.ID,
-		NewStatus:   model.ViolationStatusOpen,
-		Note:        "manual creation",
-		ChangedBy:   &principal.UserID,
-	}); err != nil {
-		return nil, err
+		if err := repos.Violations.LogStatusChange(ctx, &model.ViolationStatusLog{
+			ViolationID: violation.ID,
+			NewStatus:   model.ViolationStatusOpen,
+			Note:        "manual creation",
+			ChangedBy:   &principal.UserID,
+		}); err != nil {
+			return err
+		}
+
+		return recordViolationEvent(ctx, repos, model.EventViolationCreated, *violation, trip, nil, "manual creation", &principal.UserID)
+	})
+	if err != nil {
+		return nil, translateRepoError(err)
 	}
+	metrics.ViolationCreated(string(violation.Type), string(violation.DetectedBy))
+	logger.FromContext(ctx).Info().
+		Stringer("violation_id", violation.ID).
+		Stringer("trip_id", violation.TripID).
+		Str("type", string(violation.Type)).
+		Msg("violation created manually")
 
 	created, err := s.violationRepo.GetByID(ctx, scope, violation.ID)
 	if err != nil {
@@ -213,9 +533,12 @@ func (s *ViolationService) CreateManual(ctx context.Context, principal model.Pri
 	return &record, nil
 }
 
-func (s *ViolationService) UpdateStatus(ctx context.Context, principal model.Principal, violationID uuid.UUID, target model.ViolationStatus, description string) error {
-	if !(principal.IsAkimat() || principal.IsKgu()) {
-		return ErrPermissionDenied
+func (s *ViolationService) UpdateStatus(ctx context.Context, principal model.Principal, violationID uuid.UUID, expectedVersion int64, target model.ViolationStatus, description string) error {
+	ctx, span := startSpan(ctx, "ViolationService.UpdateStatus", principal)
+	defer span.End()
+
+	if err := authorize(s.access, principal, policy.ViolationSetStatus, ""); err != nil {
+		return err
 	}
 
 	scope, err := s.resolveScope(ctx, principal)
@@ -231,6 +554,10 @@ func (s *ViolationService) UpdateStatus(ctx context.Context, principal model.Pri
 		return err
 	}
 
+	if violation.Version != expectedVersion {
+		return ErrPrecondition
+	}
+
 	if violation.Status == model.ViolationStatusCanceled || violation.Status == model.ViolationStatusFixed {
 		return ErrInvalidStatus
 	}
@@ -239,21 +566,36 @@ func (s *ViolationService) UpdateStatus(ctx context.Context, principal model.Pri
 		return ErrInvalidStatus
 	}
 
-	if err := s.violationRepo.UpdateStatus(ctx, violation.ID, target, description); err != nil {
+	if err := authorize(s.access, principal, policy.ViolationSetStatus, string(violation.Status)); err != nil {
 		return err
 	}
 
 	prev := violation.Status
-	if err := s.violationRepo.LogStatusChange(ctx, &model.ViolationStatusLog{
-		ViolationID: violation.ID,
-		OldStatus:   &prev,
-		NewStatus:   target,
-		Note:        description,
-		ChangedBy:   &principal.UserID,
-	}); err != nil {
-		return err
-	}
+	err = s.uow.Do(ctx, func(repos repository.Repos) error {
+		if err := repos.Violations.UpdateStatus(ctx, violation.ID, violation.Version, target, description); err != nil {
+			return err
+		}
+		if err := repos.Violations.LogStatusChange(ctx, &model.ViolationStatusLog{
+			ViolationID: violation.ID,
+			OldStatus:   &prev,
+			NewStatus:   target,
+			Note:        description,
+			ChangedBy:   &principal.UserID,
+		}); err != nil {
+			return err
+		}
 
+		violation.Status = target
+		return recordViolationEvent(ctx, repos, model.EventViolationStatusChanged, *violation, violation.Trip, &prev, description, &principal.UserID)
+	})
+	if err != nil {
+		return translateRepoError(err)
+	}
+	logger.FromContext(ctx).Info().
+		Stringer("violation_id", violation.ID).
+		Str("from_status", string(prev)).
+		Str("to_status", string(target)).
+		Msg("violation status changed")
 	return nil
 }
 
@@ -333,6 +675,36 @@ func buildViolationRecord(v model.Violation, summary repository.AppealSummary) m
 	return record
 }
 
+func lookupActor(actors map[uuid.UUID]model.ActorBrief, id *uuid.UUID, fallbackRole model.UserRole) *model.ActorBrief {
+	if id == nil {
+		system := model.SystemActor
+		return &system
+	}
+	actor, ok := actors[*id]
+	if !ok {
+		actor = model.ActorBrief{ID: *id}
+	}
+	if fallbackRole != "" {
+		actor.Role = fallbackRole
+	}
+	return &actor
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func decodeCursor(value string) (*repository.Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	cursor, err := repository.DecodeCursor(value)
+	if err != nil {
+		return nil, InvalidField("cursor", "malformed cursor")
+	}
+	return cursor, nil
+}
+
 func (s *ViolationService) resolveScope(ctx context.Context, principal model.Principal) (model.Scope, error) {
 	scope, err := s.scopeRepo.ResolveScope(ctx, principal)
 	if err != nil {
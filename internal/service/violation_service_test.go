@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"violation-service/internal/model"
+)
+
+// Технической области список нарушений показывает только нарушения с апелляцией
+// CAMERA_ERROR — то же условие проверяет model.Scope.AllowsEvent для вебхуков.
+func TestBuildViolationFilterRequiresCameraAppealForTechnicalScope(t *testing.T) {
+	tests := []struct {
+		scope model.ScopeType
+		want  bool
+	}{
+		{scope: model.ScopeTechnical, want: true},
+		{scope: model.ScopeCity},
+		{scope: model.ScopeKgu},
+		{scope: model.ScopeContractor},
+		{scope: model.ScopeDriver},
+		{scope: model.ScopeLandfill},
+	}
+	for _, tt := range tests {
+		filter := buildViolationFilter(model.Scope{Type: tt.scope}, ListViolationsOptions{})
+		if filter.RequireCameraAppeal != tt.want {
+			t.Errorf("%s: RequireCameraAppeal = %v, want %v", tt.scope, filter.RequireCameraAppeal, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+)
+
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2}$`)
+
+type ViolationTypeService struct {
+	typeRepo *repository.ViolationTypeRepository
+	access   *policy.Policy
+}
+
+func NewViolationTypeService(typeRepo *repository.ViolationTypeRepository, accessPolicy *policy.Policy) *ViolationTypeService {
+	return &ViolationTypeService{typeRepo: typeRepo, access: accessPolicy}
+}
+
+// ViolationTypeInput — изменяемые поля справочника; nil-поля при обновлении сохраняют
+// прежнее значение, при создании берутся значения по умолчанию.
+type ViolationTypeInput struct {
+	Names            model.LocalizedNames
+	DefaultSeverity  *model.ViolationSeverity
+	DetectionSources []model.ViolationDetectedBy
+	Appealable       *bool
+	IsActive         *bool
+}
+
+// List доступен всем ролям: справочник нужен фронтенду для фильтров и форм.
+func (s *ViolationTypeService) List(ctx context.Context, includeInactive bool) ([]model.ViolationTypeDefinition, error) {
+	return s.typeRepo.List(ctx, includeInactive)
+}
+
+func (s *ViolationTypeService) Get(ctx context.Context, code model.ViolationType) (*model.ViolationTypeDefinition, error) {
+	definition, err := s.typeRepo.Get(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return definition, nil
+}
+
+func (s *ViolationTypeService) Create(ctx context.Context, principal model.Principal, code model.ViolationType, input ViolationTypeInput) (*model.ViolationTypeDefinition, error) {
+	if err := authorize(s.access, principal, policy.ViolationTypeManage, ""); err != nil {
+		return nil, err
+	}
+	if !model.IsValidViolationTypeCode(code) {
+		return nil, InvalidField("code", "must be upper-case letters, digits and underscores")
+	}
+
+	definition := &model.ViolationTypeDefinition{
+		Code:             code,
+		DefaultSeverity:  model.ViolationSeverityMedium,
+		DetectionSources: model.DetectionSourceList{},
+		Appealable:       true,
+		IsActive:         true,
+	}
+	if input.Names == nil {
+		return nil, InvalidField("names", "at least one name is required")
+	}
+	if err := applyViolationTypeInput(definition, input); err != nil {
+		return nil, err
+	}
+	if err := s.typeRepo.Create(ctx, definition); err != nil {
+		return nil, translateRepoError(err)
+	}
+	return s.Get(ctx, code)
+}
+
+func (s *ViolationTypeService) Update(ctx context.Context, principal model.Principal, code model.ViolationType, input ViolationTypeInput) (*model.ViolationTypeDefinition, error) {
+	if err := authorize(s.access, principal, policy.ViolationTypeManage, ""); err != nil {
+		return nil, err
+	}
+	definition, err := s.Get(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyViolationTypeInput(definition, input); err != nil {
+		return nil, err
+	}
+	if err := s.typeRepo.Update(ctx, definition); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return s.Get(ctx, code)
+}
+
+// Delete удаляет неиспользуемый тип; тип с нарушениями даёт ErrConflict — его можно деактивировать.
+func (s *ViolationTypeService) Delete(ctx context.Context, principal model.Principal, code model.ViolationType) error {
+	if err := authorize(s.access, principal, policy.ViolationTypeManage, ""); err != nil {
+		return err
+	}
+	if err := s.typeRepo.Delete(ctx, code); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return translateRepoError(err)
+	}
+	return nil
+}
+
+func applyViolationTypeInput(definition *model.ViolationTypeDefinition, input ViolationTypeInput) error {
+	if input.Names != nil {
+		names := make(model.LocalizedNames, len(input.Names))
+		for lang, name := range input.Names {
+			lang = strings.ToLower(strings.TrimSpace(lang))
+			name = strings.TrimSpace(name)
+			if !languageCodePattern.MatchString(lang) {
+				return InvalidField("names", "keys must be two-letter language codes")
+			}
+			if name != "" {
+				names[lang] = name
+			}
+		}
+		if len(names) == 0 {
+			return InvalidField("names", "at least one name is required")
+		}
+		definition.Names = names
+	}
+	if input.DefaultSeverity != nil {
+		if !slices.Contains(model.KnownSeverities, *input.DefaultSeverity) {
+			return InvalidField("default_severity", "unknown severity", allowedValues(model.KnownSeverities)...)
+		}
+		definition.DefaultSeverity = *input.DefaultSeverity
+	}
+	if input.DetectionSources != nil {
+		sources := make(model.DetectionSourceList, 0, len(input.DetectionSources))
+		for _, source := range input.DetectionSources {
+			if !slices.Contains(model.KnownDetectionSources, source) {
+				return InvalidField("detection_sources", "unknown detection source", allowedValues(model.KnownDetectionSources)...)
+			}
+			if !sources.Contains(source) {
+				sources = append(sources, source)
+			}
+		}
+		definition.DetectionSources = sources
+	}
+	if input.Appealable != nil {
+		definition.Appealable = *input.Appealable
+	}
+	if input.IsActive != nil {
+		definition.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+// validateManualViolation сверяет тип, источник и серьёзность с справочником и
+// подставляет серьёзность по умолчанию.
+func validateManualViolation(ctx context.Context, typeRepo *repository.ViolationTypeRepository, input *CreateViolationInput) error {
+	definition, err := typeRepo.Get(ctx, input.Type)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if definition == nil || !definition.IsActive {
+		active, err := typeRepo.List(ctx, false)
+		if err != nil {
+			return err
+		}
+		codes := make([]string, 0, len(active))
+		for _, item := range active {
+			codes = append(codes, string(item.Code))
+		}
+		return InvalidField("type", "unknown or inactive violation type", codes...)
+	}
+
+	sources := model.DetectionSourceList(model.KnownDetectionSources)
+	if len(definition.DetectionSources) > 0 {
+		sources = definition.DetectionSources
+	}
+	if !sources.Contains(input.DetectedBy) {
+		return InvalidField("detected_by", "not allowed for type "+string(definition.Code), allowedValues(sources)...)
+	}
+
+	if input.Severity == "" {
+		input.Severity = definition.DefaultSeverity
+	}
+	if !slices.Contains(model.KnownSeverities, input.Severity) {
+		return InvalidField("severity", "unknown severity", allowedValues(model.KnownSeverities)...)
+	}
+	return nil
+}
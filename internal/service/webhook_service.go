@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"violation-service/internal/model"
+	"violation-service/internal/policy"
+	"violation-service/internal/repository"
+	"violation-service/internal/webhook"
+)
+
+const minWebhookSecretLength = 16
+
+var (
+	errInvalidWebhookURL    = InvalidField("url", "must be an absolute http or https URL")
+	errNonPublicWebhookURL  = InvalidField("url", "must point to a public address")
+	errUnresolvedWebhookURL = InvalidField("url", "host does not resolve")
+)
+
+type WebhookService struct {
+	webhookRepo *repository.WebhookRepository
+	access      *policy.Policy
+}
+
+func NewWebhookService(webhookRepo *repository.WebhookRepository, accessPolicy *policy.Policy) *WebhookService {
+	return &WebhookService{webhookRepo: webhookRepo, access: accessPolicy}
+}
+
+type WebhookInput struct {
+	URL          *string
+	EventTypes   []model.EventType
+	IsActive     *bool
+	Secret       *string
+	RotateSecret bool
+}
+
+func (s *WebhookService) List(ctx context.Context, principal model.Principal) ([]model.WebhookSubscription, error) {
+	if err := authorize(s.access, principal, policy.WebhookManage, ""); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.ListSubscriptions(ctx, principal.OrgID)
+}
+
+func (s *WebhookService) Get(ctx context.Context, principal model.Principal, id uuid.UUID) (*model.WebhookSubscription, error) {
+	if err := authorize(s.access, principal, policy.WebhookManage, ""); err != nil {
+		return nil, err
+	}
+	sub, err := s.webhookRepo.GetSubscription(ctx, principal.OrgID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Create регистрирует подписку организации пользователя. Секрет возвращается только здесь
+// и при ротации.
+func (s *WebhookService) Create(ctx context.Context, principal model.Principal, input WebhookInput) (*model.WebhookSubscriptionWithSecret, error) {
+	if err := authorize(s.access, principal, policy.WebhookManage, ""); err != nil {
+		return nil, err
+	}
+	if input.URL == nil {
+		return nil, errInvalidWebhookURL
+	}
+	if err := validateWebhookURL(ctx, *input.URL); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(input.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret, err := resolveSecret(input.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := &model.WebhookSubscription{
+		OrganizationID: principal.OrgID,
+		OwnerRole:      principal.Role,
+		URL:            strings.TrimSpace(*input.URL),
+		Secret:         secret,
+		EventTypes:     model.EventTypeList(input.EventTypes),
+		IsActive:       true,
+		CreatedBy:      &principal.UserID,
+	}
+	if input.IsActive != nil {
+		sub.IsActive = *input.IsActive
+	}
+
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return &model.WebhookSubscriptionWithSecret{WebhookSubscription: *sub, Secret: secret}, nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, principal model.Principal, id uuid.UUID, input WebhookInput) (*model.WebhookSubscriptionWithSecret, error) {
+	sub, err := s.Get(ctx, principal, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		if err := validateWebhookURL(ctx, *input.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = strings.TrimSpace(*input.URL)
+	}
+	if input.EventTypes != nil {
+		if err := validateEventTypes(input.EventTypes); err != nil {
+			return nil, err
+		}
+		sub.EventTypes = model.EventTypeList(input.EventTypes)
+	}
+	if input.IsActive != nil {
+		sub.IsActive = *input.IsActive
+	}
+
+	result := &model.WebhookSubscriptionWithSecret{}
+	if input.Secret != nil || input.RotateSecret {
+		secret, err := resolveSecret(input.Secret)
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+		result.Secret = secret
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	result.WebhookSubscription = *sub
+	return result, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, principal model.Principal, id uuid.UUID) error {
+	if err := authorize(s.access, principal, policy.WebhookManage, ""); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.DeleteSubscription(ctx, principal.OrgID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+type WebhookDeliveryListOptions struct {
+	Statuses []model.WebhookDeliveryStatus
+	Cursor   string
+	Limit    int
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, principal model.Principal, id uuid.UUID, opts WebhookDeliveryListOptions) (*model.Page[model.WebhookDelivery], error) {
+	sub, err := s.Get(ctx, principal, id)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := decodeCursor(opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	page, err := s.webhookRepo.ListDeliveries(ctx, repository.WebhookDeliveryFilter{
+		SubscriptionID: sub.ID,
+		Statuses:       opts.Statuses,
+		Cursor:         cursor,
+		Limit:          opts.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, principal model.Principal, id, deliveryID uuid.UUID) error {
+	sub, err := s.Get(ctx, principal, id)
+	if err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Redeliver(ctx, sub.ID, deliveryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// validateWebhookURL принимает только абсолютные http(s) URL с публичным адресом, чтобы
+// подписка не превращалась в способ слать подписанные запросы во внутреннюю сеть.
+func validateWebhookURL(ctx context.Context, raw string) error {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Hostname() == "" {
+		return errInvalidWebhookURL
+	}
+	if err := webhook.CheckHost(ctx, parsed.Hostname()); err != nil {
+		if errors.Is(err, webhook.ErrForbiddenAddress) {
+			return errNonPublicWebhookURL
+		}
+		return errUnresolvedWebhookURL
+	}
+	return nil
+}
+
+func validateEventTypes(types []model.EventType) error {
+	if len(types) == 0 {
+		return InvalidField("event_types", "at least one event type is required", allowedValues(model.KnownEventTypes)...)
+	}
+	for _, t := range types {
+		if !model.IsKnownEventType(t) {
+			return InvalidField("event_types", "unknown event type "+string(t), allowedValues(model.KnownEventTypes)...)
+		}
+	}
+	return nil
+}
+
+// resolveSecret возвращает переданный секрет либо генерирует новый.
+func resolveSecret(provided *string) (string, error) {
+	if provided != nil && strings.TrimSpace(*provided) != "" {
+		secret := strings.TrimSpace(*provided)
+		if len(secret) < minWebhookSecretLength {
+			return "", InvalidField("secret", fmt.Sprintf("must be at least %d characters", minWebhookSecretLength))
+		}
+		return secret, nil
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,83 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// NeedInfoResolver — сторона сервиса апелляций, которую дёргает NeedInfoJob.
+type NeedInfoResolver interface {
+	RemindStaleNeedInfo(ctx context.Context, now time.Time, defaultWindow, grace time.Duration, limit int) (int, error)
+	RejectStaleNeedInfo(ctx context.Context, now time.Time, grace time.Duration, limit int) (int, error)
+}
+
+type NeedInfoConfig struct {
+	Interval      time.Duration
+	BatchSize     int
+	DefaultWindow time.Duration
+	Grace         time.Duration
+}
+
+// NeedInfoJob доводит до конца апелляции, застрявшие в NEED_INFO: по истечении окна
+// организации отправляет одно напоминание, а через Grace после него отклоняет апелляцию.
+type NeedInfoJob struct {
+	resolver NeedInfoResolver
+	cfg      NeedInfoConfig
+	log      zerolog.Logger
+}
+
+func NewNeedInfoJob(resolver NeedInfoResolver, cfg NeedInfoConfig, log zerolog.Logger) *NeedInfoJob {
+	return &NeedInfoJob{
+		resolver: resolver,
+		cfg:      cfg,
+		log:      log,
+	}
+}
+
+func (j *NeedInfoJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.runOnce(ctx); err != nil && ctx.Err() == nil {
+			j.log.Error().Err(err).Msg("need info job failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce сначала отклоняет, потом напоминает: напоминание, отправленное в этом же
+// проходе, не должно сразу привести к отклонению даже при нулевом Grace.
+func (j *NeedInfoJob) runOnce(ctx context.Context) error {
+	for ctx.Err() == nil {
+		rejected, err := j.resolver.RejectStaleNeedInfo(ctx, time.Now(), j.cfg.Grace, j.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if rejected > 0 {
+			j.log.Info().Int("appeals", rejected).Msg("stale need info appeals auto-rejected")
+		}
+		if rejected < j.cfg.BatchSize {
+			break
+		}
+	}
+	for ctx.Err() == nil {
+		reminded, err := j.resolver.RemindStaleNeedInfo(ctx, time.Now(), j.cfg.DefaultWindow, j.cfg.Grace, j.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if reminded > 0 {
+			j.log.Info().Int("appeals", reminded).Msg("need info reminders sent")
+		}
+		if reminded < j.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
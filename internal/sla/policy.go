@@ -0,0 +1,64 @@
+package sla
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"violation-service/internal/model"
+)
+
+// Policy хранит сроки рассмотрения апелляций по статусу и, при необходимости, по причине.
+// Ключ "STATUS:REASON" перекрывает ключ "STATUS"; нулевая длительность отключает срок.
+type Policy struct {
+	byStatus map[model.AppealStatus]time.Duration
+	byReason map[model.AppealStatus]map[model.AppealReasonCode]time.Duration
+}
+
+// NewPolicy разбирает правила вида {"SUBMITTED": 48h, "UNDER_REVIEW:CAMERA_ERROR": 24h}.
+func NewPolicy(rules map[string]time.Duration) (*Policy, error) {
+	p := &Policy{
+		byStatus: make(map[model.AppealStatus]time.Duration),
+		byReason: make(map[model.AppealStatus]map[model.AppealReasonCode]time.Duration),
+	}
+	for key, duration := range rules {
+		if duration < 0 {
+			return nil, fmt.Errorf("sla rule %s: negative duration", key)
+		}
+		statusPart, reasonPart, hasReason := strings.Cut(strings.ToUpper(strings.TrimSpace(key)), ":")
+		status := model.AppealStatus(statusPart)
+		if !status.IsActive() {
+			return nil, fmt.Errorf("sla rule %s: status must be one of SUBMITTED, UNDER_REVIEW, NEED_INFO", key)
+		}
+		if !hasReason {
+			p.byStatus[status] = duration
+			continue
+		}
+		reason := model.AppealReasonCode(reasonPart)
+		if !model.IsKnownAppealReason(reason) {
+			return nil, fmt.Errorf("sla rule %s: unknown reason code %s", key, reasonPart)
+		}
+		if p.byReason[status] == nil {
+			p.byReason[status] = make(map[model.AppealReasonCode]time.Duration)
+		}
+		p.byReason[status][reason] = duration
+	}
+	return p, nil
+}
+
+// DueAt возвращает крайний срок для апелляции, вошедшей в status в момент from,
+// или nil, если для статуса срок не задан.
+func (p *Policy) DueAt(status model.AppealStatus, reason model.AppealReasonCode, from time.Time) *time.Time {
+	if p == nil || !status.IsActive() {
+		return nil
+	}
+	duration, ok := p.byReason[status][reason]
+	if !ok {
+		duration = p.byStatus[status]
+	}
+	if duration <= 0 {
+		return nil
+	}
+	due := from.Add(duration)
+	return &due
+}
@@ -0,0 +1,73 @@
+package sla
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// OverdueFlagger — сторона сервиса апелляций, которую дёргает Sweeper.
+type OverdueFlagger interface {
+	BackfillDueDates(ctx context.Context) (int, error)
+	FlagOverdue(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type SweeperConfig struct {
+	Interval  time.Duration
+	BatchSize int
+}
+
+// Sweeper периодически помечает апелляции с истёкшим due_at. При старте он один раз
+// проставляет due_at активным апелляциям, созданным до включения SLA.
+type Sweeper struct {
+	flagger OverdueFlagger
+	cfg     SweeperConfig
+	log     zerolog.Logger
+}
+
+func NewSweeper(flagger OverdueFlagger, cfg SweeperConfig, log zerolog.Logger) *Sweeper {
+	return &Sweeper{
+		flagger: flagger,
+		cfg:     cfg,
+		log:     log,
+	}
+}
+
+func (s *Sweeper) Run(ctx context.Context) {
+	if n, err := s.flagger.BackfillDueDates(ctx); err != nil && ctx.Err() == nil {
+		s.log.Error().Err(err).Msg("sla due date backfill failed")
+	} else if n > 0 {
+		s.log.Info().Int("appeals", n).Msg("sla due dates backfilled")
+	}
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sweep(ctx); err != nil && ctx.Err() == nil {
+			s.log.Error().Err(err).Msg("sla sweep failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) error {
+	for ctx.Err() == nil {
+		flagged, err := s.flagger.FlagOverdue(ctx, time.Now(), s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if flagged > 0 {
+			s.log.Info().Int("appeals", flagged).Msg("overdue appeals flagged")
+		}
+		if flagged < s.cfg.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
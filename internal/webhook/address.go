@@ -0,0 +1,81 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenAddress — адрес подписчика указывает во внутреннюю сеть.
+var ErrForbiddenAddress = errors.New("webhook target resolves to a non-public address")
+
+// sharedAddressSpace — 100.64.0.0/10 (RFC 6598), адреса CGNAT и многих сетей кластеров.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicAddress сообщает, можно ли слать вебхуки на ip: loopback, link-local (в том числе
+// метаданные облака 169.254.169.254), частные и служебные диапазоны запрещены.
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsValid() &&
+		!ip.IsUnspecified() &&
+		!ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!sharedAddressSpace.Contains(ip)
+}
+
+// CheckHost проверяет хост из URL подписки: IP-литерал — сразу, имя — по всем адресам,
+// в которые оно разрешается. Воркер повторяет проверку при соединении, поэтому смена
+// DNS-записи после регистрации не открывает доступ во внутреннюю сеть.
+func CheckHost(ctx context.Context, host string) error {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !PublicAddress(ip) {
+			return ErrForbiddenAddress
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	for _, ip := range addrs {
+		if !PublicAddress(ip) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// dialControl отклоняет соединение с непубличным адресом уже после разрешения имени.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !PublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// newClient собирает HTTP-клиент воркера. Прокси из окружения не используется: проверка
+// адреса видела бы адрес прокси, а не подписчика.
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
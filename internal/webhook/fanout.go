@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"violation-service/internal/model"
+	"violation-service/internal/repository"
+)
+
+// Fanout реализует outbox.Publisher: раскладывает доменное событие в очередь
+// доставок для каждой подписки, чья организация видит это событие.
+type Fanout struct {
+	webhookRepo *repository.WebhookRepository
+	scopeRepo   *repository.ScopeRepository
+	appealRepo  *repository.AppealRepository
+}
+
+func NewFanout(webhookRepo *repository.WebhookRepository, scopeRepo *repository.ScopeRepository, appealRepo *repository.AppealRepository) *Fanout {
+	return &Fanout{
+		webhookRepo: webhookRepo,
+		scopeRepo:   scopeRepo,
+		appealRepo:  appealRepo,
+	}
+}
+
+func (f *Fanout) Publish(ctx context.Context, event model.OutboxEvent) error {
+	subs, err := f.webhookRepo.ActiveSubscriptionsFor(ctx, event.EventType)
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	var payload model.EventPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	cameraChecked := false
+	for _, sub := range subs {
+		scope, err := f.scopeRepo.ResolveScope(ctx, model.Principal{
+			OrgID: sub.OrganizationID,
+			Role:  sub.OwnerRole,
+		})
+		if err != nil {
+			if errors.Is(err, repository.ErrScopeUnsupported) {
+				continue
+			}
+			return err
+		}
+		// Апелляции ищутся только для технических подписок на события нарушения.
+		if scope.Type == model.ScopeTechnical && payload.AppealID == nil && !cameraChecked {
+			if payload.CameraAppeal, err = f.appealRepo.HasCameraAppeal(ctx, payload.ViolationID); err != nil {
+				return err
+			}
+			cameraChecked = true
+		}
+		if !scope.AllowsEvent(payload) {
+			continue
+		}
+		if err := f.webhookRepo.EnqueueDelivery(ctx, &model.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.EventType,
+			Payload:        body,
+			Status:         model.WebhookDeliveryPending,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	HeaderSignature = "X-Snowops-Signature"
+	HeaderTimestamp = "X-Snowops-Timestamp"
+	HeaderEvent     = "X-Snowops-Event"
+	HeaderDelivery  = "X-Snowops-Delivery"
+)
+
+// Sign вычисляет подпись "sha256=<hex>" от строки "<timestamp>.<body>".
+// Временная метка входит в подпись, чтобы получатель мог отсекать повторы.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"violation-service/internal/model"
+	"violation-service/internal/outbox"
+	"violation-service/internal/repository"
+)
+
+type WorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	HTTPTimeout  time.Duration
+	// ClaimLease — на сколько скрываются взятые доставки, пока идёт отправка;
+	// должна покрывать BatchSize × HTTPTimeout.
+	ClaimLease time.Duration
+}
+
+// Worker отправляет доставки из webhook_deliveries с подписью HMAC-SHA256.
+// После MaxAttempts неудач доставка переводится в DEAD и ждёт ручного redeliver.
+type Worker struct {
+	repo   *repository.WebhookRepository
+	client *http.Client
+	cfg    WorkerConfig
+	log    zerolog.Logger
+}
+
+func NewWorker(repo *repository.WebhookRepository, cfg WorkerConfig, log zerolog.Logger) *Worker {
+	return &Worker{
+		repo:   repo,
+		client: newClient(cfg.HTTPTimeout),
+		cfg:    cfg,
+		log:    log,
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.processBatch(ctx); err != nil && ctx.Err() == nil {
+			w.log.Error().Err(err).Msg("webhook worker iteration failed")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch отправляет одну пачку. HTTP-запросы идут вне транзакции: медленный
+// подписчик не держит блокировки строк и соединение пула, а результат каждой
+// доставки записывается отдельным коротким запросом.
+func (w *Worker) processBatch(ctx context.Context) error {
+	deliveries, err := w.repo.ClaimDue(ctx, w.cfg.BatchSize, w.cfg.ClaimLease)
+	if err != nil || len(deliveries) == 0 {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.SubscriptionID)
+	}
+	subs, err := w.repo.SubscriptionsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok || !sub.IsActive {
+			if err := w.repo.MarkFailed(ctx, delivery.ID, nil, "subscription inactive", time.Now(), true); err != nil {
+				return err
+			}
+			continue
+		}
+
+		statusCode, sendErr := w.send(ctx, sub, delivery)
+		if sendErr == nil {
+			if err := w.repo.MarkDelivered(ctx, delivery.ID, statusCode); err != nil {
+				return err
+			}
+			continue
+		}
+
+		attempt := delivery.Attempts + 1
+		dead := attempt >= w.cfg.MaxAttempts
+		next := time.Now().Add(outbox.Backoff(attempt, w.cfg.RetryBase, w.cfg.RetryMax))
+		var code *int
+		if statusCode > 0 {
+			code = &statusCode
+		}
+		w.log.Warn().Err(sendErr).
+			Str("delivery_id", delivery.ID.String()).
+			Str("subscription_id", sub.ID.String()).
+			Int("attempt", attempt).
+			Bool("dead_letter", dead).
+			Msg("webhook delivery failed")
+		if err := w.repo.MarkFailed(ctx, delivery.ID, code, sendErr.Error(), next, dead); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Worker) send(ctx context.Context, sub model.WebhookSubscription, delivery model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderTimestamp, fmt.Sprintf("%d", timestamp))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, delivery.Payload))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}